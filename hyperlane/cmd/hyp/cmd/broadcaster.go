@@ -1,37 +1,40 @@
 package cmd
 
 import (
-	"context"
 	"encoding/hex"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
-	"time"
+	"strconv"
+	"strings"
 
 	"github.com/celestiaorg/celestia-app/v6/app/encoding"
-	abci "github.com/cometbft/cometbft/abci/types"
-	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/celestiaorg/hyp-deploy/pkg/broadcaster"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
-	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	"google.golang.org/grpc"
 )
 
 const (
-	denom     = "utia"
-	feeAmount = 800
-	gasLimit  = 200000
-)
+	denom           = "utia"
+	feeAmount       = 800
+	defaultGasLimit = 200000
+
+	// gasAuto requires gas to be estimated by simulation, failing instead of falling back to the default gas limit.
+	gasAuto = "auto"
 
-var (
-	mnemonic = getEnvOrDefault("HYP_MNEMONIC", "sphere exhibit essay fancy okay tuna leaf culture elbow drum trip exchange scorpion excuse parent sun make spot chunk mouse tenant shoe hurt scale")
-	chainID  = getEnvOrDefault("HYP_CHAIN_ID", "celestia-zkevm-testnet")
+	// broadcastModeBlock, broadcastModeSync and broadcastModeAsync are the values accepted by --broadcast-mode.
+	broadcastModeBlock = "block"
+	broadcastModeSync  = "sync"
+	broadcastModeAsync = "async"
 )
 
+var chainID = getEnvOrDefault("HYP_CHAIN_ID", "celestia-zkevm-testnet")
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -39,119 +42,194 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
-type Broadcaster struct {
-	enc encoding.Config
+// NewBroadcaster returns a broadcaster.Broadcaster configured from the signing, gas, fee and confirmation flags.
+func NewBroadcaster(enc encoding.Config, grpcConn *grpc.ClientConn) (*broadcaster.Broadcaster, error) {
+	kr, keyName, _, err := newKeyring(enc)
+	if err != nil {
+		return nil, err
+	}
 
-	authService authtypes.QueryClient
-	txService   txtypes.ServiceClient
+	confirmer, err := newConfirmer(confirmStrategy, txtypes.NewServiceClient(grpcConn))
+	if err != nil {
+		return nil, err
+	}
 
-	address sdk.AccAddress
+	cfg := broadcaster.Config{
+		ChainID:       chainID,
+		Keyring:       kr,
+		KeyName:       keyName,
+		SignMode:      signMode(),
+		GasAdjustment: gasAdjustment,
+		Confirmer:     confirmer,
+		Confirmations: confirmations,
+		Retry:         broadcaster.RetryOptions{Retries: grpcRetries, Delay: grpcRetryDelay},
+		GenerateOnly:  generateOnly,
+		Output:        documentOutput(),
+	}
 
-	kr keyring.Keyring
-}
+	switch broadcastMode {
+	case broadcastModeBlock:
+		cfg.Mode = broadcaster.BroadcastModeBlock
+	case broadcastModeSync:
+		cfg.Mode = broadcaster.BroadcastModeSync
+	case broadcastModeAsync:
+		cfg.Mode = broadcaster.BroadcastModeAsync
+	default:
+		return nil, fmt.Errorf("unknown broadcast mode %q, expected %q, %q or %q", broadcastMode, broadcastModeBlock, broadcastModeSync, broadcastModeAsync)
+	}
 
-func NewBroadcaster(enc encoding.Config, grpcConn *grpc.ClientConn) *Broadcaster {
-	// Recover private key from mnemonic
-	secp256k1Derv := hd.Secp256k1.Derive()
-	privKey, err := secp256k1Derv(mnemonic, "", hd.CreateHDPath(118, 0, 0).String())
-	if err != nil {
-		log.Fatalf("failed to derive pk from mnemonic: %v", err)
+	switch gasSetting {
+	case "":
+		cfg.FallbackGasLimit = defaultGasLimit
+	case gasAuto:
+	default:
+		if cfg.GasLimit, err = strconv.ParseUint(gasSetting, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid --gas %q, expected %q or an integer: %w", gasSetting, gasAuto, err)
+		}
 	}
 
-	pk := secp256k1.PrivKey{Key: privKey}
-	signerAddr := sdk.AccAddress(pk.PubKey().Address())
+	if gasPrices != "" {
+		if cfg.GasPrices, err = sdk.ParseDecCoins(gasPrices); err != nil {
+			return nil, fmt.Errorf("invalid --gas-prices %q: %w", gasPrices, err)
+		}
+	}
 
-	kr := keyring.NewInMemory(enc.Codec)
-	if err := kr.ImportPrivKeyHex(signerAddr.String(), hex.EncodeToString(pk.Bytes()), pk.Type()); err != nil {
-		log.Fatalf("key import failed")
+	cfg.Fees = sdk.NewCoins(sdk.NewInt64Coin(denom, feeAmount))
+	if fees != "" {
+		if cfg.Fees, err = sdk.ParseCoinsNormalized(fees); err != nil {
+			return nil, fmt.Errorf("invalid --fees %q: %w", fees, err)
+		}
 	}
 
-	return &Broadcaster{
-		enc:         enc,
-		authService: authtypes.NewQueryClient(grpcConn),
-		txService:   txtypes.NewServiceClient(grpcConn),
-		address:     signerAddr,
-		kr:          kr,
+	if feeGranter != "" {
+		if cfg.FeeGranter, err = sdk.AccAddressFromBech32(feeGranter); err != nil {
+			return nil, fmt.Errorf("invalid --fee-granter %q: %w", feeGranter, err)
+		}
 	}
+
+	return broadcaster.New(enc, grpcConn, cfg)
 }
 
-func (b *Broadcaster) BroadcastTx(ctx context.Context, msgs ...sdk.Msg) *sdk.TxResponse {
-	accRes, err := b.authService.Account(ctx, &authtypes.QueryAccountRequest{Address: b.address.String()})
-	if err != nil {
-		log.Fatalf("failed to query account: %v", err)
+// newKeyring returns the keyring selected by --keyring-backend along with the name and address of the signing key.
+// The memory backend recovers the signing key from the configured mnemonic, other backends look up the key named
+// by --from in the keyring directory.
+func newKeyring(enc encoding.Config) (keyring.Keyring, string, sdk.AccAddress, error) {
+	if useLedger {
+		return newLedgerKeyring(enc)
 	}
 
-	var acc authtypes.BaseAccount
-	if err := b.enc.Codec.Unmarshal(accRes.Account.Value, &acc); err != nil {
-		log.Fatalf("unmarshal account: %v", err)
+	if keyringBackend == keyring.BackendMemory {
+		kr, signerAddr, err := newMnemonicKeyring(enc)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		return kr, signerAddr.String(), signerAddr, nil
 	}
 
-	txBuilder := b.enc.TxConfig.NewTxBuilder()
-	if err := txBuilder.SetMsgs(msgs...); err != nil {
-		log.Fatalf("set msgs: %v", err)
+	if fromKey == "" {
+		return nil, "", nil, fmt.Errorf("--from is required with keyring backend %q", keyringBackend)
 	}
 
-	txBuilder.SetGasLimit(gasLimit)
-	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin(denom, feeAmount)))
+	kr, err := keyring.New(sdk.KeyringServiceName(), keyringBackend, keyringDir, os.Stdin, enc.Codec)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to open keyring: %w", err)
+	}
 
-	factory := tx.Factory{}.
-		WithKeybase(b.kr).
-		WithSignMode(signing.SignMode_SIGN_MODE_DIRECT).
-		WithTxConfig(b.enc.TxConfig).
-		WithChainID(chainID).
-		WithAccountNumber(acc.AccountNumber).
-		WithSequence(acc.Sequence)
+	record, err := kr.Key(fromKey)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to find key %q in keyring: %w", fromKey, err)
+	}
 
-	if err := tx.Sign(ctx, factory, b.address.String(), txBuilder, false); err != nil {
-		log.Fatalf("failed to sign tx: %v", err)
+	signerAddr, err := record.GetAddress()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to get address of key %q: %w", fromKey, err)
 	}
 
-	txBytes, err := b.enc.TxConfig.TxEncoder()(txBuilder.GetTx())
+	return kr, fromKey, signerAddr, nil
+}
+
+// newLedgerKeyring registers the Ledger device key at the --coin-type, --account and --index derivation path in an
+// in-memory keyring. Signing with the returned keyring prompts for confirmation on the device.
+func newLedgerKeyring(enc encoding.Config) (keyring.Keyring, string, sdk.AccAddress, error) {
+	const keyName = "ledger"
+
+	kr := keyring.NewInMemory(enc.Codec)
+	record, err := kr.SaveLedgerKey(keyName, hd.Secp256k1, sdk.GetConfig().GetBech32AccountAddrPrefix(), hdCoinType, hdAccount, hdIndex)
 	if err != nil {
-		log.Fatalf("encode tx: %v", err)
+		return nil, "", nil, fmt.Errorf("failed to load ledger key (the binary must be built with -tags ledger): %w", err)
 	}
 
-	broadcastTxReq := &txtypes.BroadcastTxRequest{
-		Mode:    txtypes.BroadcastMode_BROADCAST_MODE_SYNC,
-		TxBytes: txBytes,
+	signerAddr, err := record.GetAddress()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to get ledger address: %w", err)
 	}
 
-	res, err := b.txService.BroadcastTx(ctx, broadcastTxReq)
-	if err != nil || res.TxResponse.Code != abci.CodeTypeOK {
-		log.Printf("failed response: %v\n", res.TxResponse)
-		log.Fatalf("broadcast tx failed: %v", err)
+	slog.Info("derived signer from ledger", "address", signerAddr, "hd_path", hdPath())
+	return kr, keyName, signerAddr, nil
+}
+
+// newMnemonicKeyring recovers the signing key at the --coin-type, --account and --index derivation path from the
+// configured mnemonic and imports it into an in-memory keyring.
+func newMnemonicKeyring(enc encoding.Config) (keyring.Keyring, sdk.AccAddress, error) {
+	mnemonic, err := resolveMnemonic()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	txResp, err := b.waitForTxResponse(ctx, res.TxResponse.TxHash)
+	// Recover private key from mnemonic
+	secp256k1Derv := hd.Secp256k1.Derive()
+	privKey, err := secp256k1Derv(mnemonic, "", hdPath())
 	if err != nil {
-		log.Fatalf("broadcast tx failed: %v", err)
+		// the error is not wrapped as it may echo the mnemonic
+		return nil, nil, fmt.Errorf("failed to derive pk from mnemonic")
 	}
 
-	return txResp
+	pk := secp256k1.PrivKey{Key: privKey}
+	signerAddr := sdk.AccAddress(pk.PubKey().Address())
+
+	kr := keyring.NewInMemory(enc.Codec)
+	if err := kr.ImportPrivKeyHex(signerAddr.String(), hex.EncodeToString(pk.Bytes()), pk.Type()); err != nil {
+		return nil, nil, fmt.Errorf("key import failed")
+	}
+
+	slog.Info("derived signer from mnemonic", "address", signerAddr, "hd_path", hdPath())
+	return kr, signerAddr, nil
+}
+
+// hdPath returns the BIP44 derivation path selected by --coin-type, --account and --index.
+func hdPath() string {
+	return hd.CreateHDPath(hdCoinType, hdAccount, hdIndex).String()
+}
+
+// signMode returns the sign mode used for transactions, Ledger devices only support amino JSON signing.
+func signMode() signing.SignMode {
+	if useLedger {
+		return signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+	}
+
+	return signing.SignMode_SIGN_MODE_DIRECT
 }
 
-func (b *Broadcaster) waitForTxResponse(ctx context.Context, hash string) (*sdk.TxResponse, error) {
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
-	ticker := time.NewTicker(6 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("timeout exceeded while waiting for tx confirmation: %w", ctx.Err())
-		case <-ticker.C:
-			res, err := b.txService.GetTx(ctx, &txtypes.GetTxRequest{Hash: hash})
-			if err != nil {
-				// Assume tx not found yet; treat as retryable
-				continue
-			}
-
-			if res != nil && res.TxResponse.Height > 0 {
-				return res.TxResponse, nil
-			}
+// resolveMnemonic returns the signing mnemonic from the --mnemonic flag, the --mnemonic-file flag or the
+// HYP_MNEMONIC env var, in that order of precedence.
+func resolveMnemonic() (string, error) {
+	if mnemonicFlag != "" {
+		return mnemonicFlag, nil
+	}
+
+	if mnemonicFile != "" {
+		bz, err := os.ReadFile(mnemonicFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read mnemonic file: %w", err)
 		}
+
+		return strings.TrimSpace(string(bz)), nil
+	}
+
+	if value := os.Getenv("HYP_MNEMONIC"); value != "" {
+		return value, nil
 	}
 
+	return "", fmt.Errorf("no signing mnemonic provided: set --mnemonic, --mnemonic-file or HYP_MNEMONIC")
 }
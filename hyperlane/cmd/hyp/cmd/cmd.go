@@ -1,28 +1,184 @@
 package cmd
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
+	"cosmossdk.io/math"
 	"github.com/bcp-innovations/hyperlane-cosmos/util"
 	ismtypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/01_interchain_security/types"
 	coretypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/types"
 	warptypes "github.com/bcp-innovations/hyperlane-cosmos/x/warp/types"
 	"github.com/celestiaorg/celestia-app/v6/app"
 	"github.com/celestiaorg/celestia-app/v6/app/encoding"
+	zkismtypes "github.com/celestiaorg/celestia-app/v6/x/zkism/types"
+	"github.com/celestiaorg/hyp-deploy/pkg/broadcaster"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/ethereum/go-ethereum/ethclient"
-	evclient "github.com/evstack/ev-node/pkg/rpc/client"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
+var (
+	// batchSize is the maximum number of msgs included in a single transaction by multi-message broadcasts.
+	batchSize int
+
+	// timing enables printing the wall-clock time spent on each broadcast after a deployment.
+	timing bool
+
+	// confirmations is the number of blocks to wait for after tx inclusion before a broadcast is considered final.
+	confirmations uint64
+
+	// broadcastMode selects whether broadcasts wait for confirmation (block) or return after CheckTx (sync) or
+	// submission (async).
+	broadcastMode string
+
+	// confirmStrategy selects how broadcast transactions are confirmed in block mode.
+	confirmStrategy string
+
+	// confirmTimeout bounds the wait for a tx confirmation, confirmInterval and confirmMaxInterval bound the
+	// exponential backoff between polls.
+	confirmTimeout     time.Duration
+	confirmInterval    time.Duration
+	confirmMaxInterval time.Duration
+
+	// cometRPC is the CometBFT RPC address used by the event confirmation strategy and to fetch celestia headers.
+	cometRPC string
+
+	// reuseExisting enables reusing equivalent existing components instead of creating new ones on deployment.
+	reuseExisting bool
+
+	// force allows deploying a mailbox on a local domain already used by another mailbox.
+	force bool
+
+	// skipBalanceCheck disables failing deployments early when the signer cannot cover the estimated fees.
+	skipBalanceCheck bool
+
+	// outputDir is the directory generated artifacts are written to.
+	outputDir string
+
+	// mnemonicFlag and mnemonicFile provide the signing mnemonic, overriding the HYP_MNEMONIC env var.
+	mnemonicFlag string
+	mnemonicFile string
+
+	// keyringBackend, keyringDir and fromKey select the keyring and named signing key for non-memory backends.
+	keyringBackend string
+	keyringDir     string
+	fromKey        string
+
+	// useLedger signs transactions with a Ledger hardware wallet.
+	useLedger bool
+
+	// hdCoinType, hdAccount and hdIndex select the BIP44 path the signing key is derived at from the mnemonic or
+	// Ledger device.
+	hdCoinType uint32
+	hdAccount  uint32
+	hdIndex    uint32
+
+	// gasSetting is either a fixed gas limit, "auto" or empty to simulate with a fallback to the default gas limit.
+	gasSetting string
+
+	// gasAdjustment scales the simulated gas used to derive the gas limit.
+	gasAdjustment float64
+
+	// fees is a fixed tx fee such as 1000utia, gasPrices derives the fee from the gas limit such as 0.025utia.
+	fees      string
+	gasPrices string
+
+	// feeGranter is the address paying tx fees from a feegrant allowance to the signer.
+	feeGranter string
+
+	// grpcRetries and grpcRetryDelay bound the retries of account queries and broadcasts failing at the
+	// transport level.
+	grpcRetries    int
+	grpcRetryDelay time.Duration
+
+	// grpcInsecure disables TLS on the gRPC connection, the grpcTLS* paths configure server verification and mTLS.
+	grpcInsecure bool
+	grpcTLSCA    string
+	grpcTLSCert  string
+	grpcTLSKey   string
+
+	// grpcSocket is the unix socket of the hyp daemon gRPC calls are forwarded through instead of dialing the node.
+	grpcSocket string
+
+	// collateralDenom is the origin denom of the collateral token created by deployments.
+	collateralDenom string
+
+	// hookType selects the hook deployed as the default and required hook of new mailboxes.
+	hookType string
+
+	// igpBeneficiary and igpGasConfigs configure the InterchainGasPaymaster deployed with --hook-type igp.
+	igpBeneficiary string
+	igpGasConfigs  []string
+
+	// groth16VkeyPath is the path to the groth16 verifying key of zk isms deployed by deploy-zkism and setup-zkism.
+	groth16VkeyPath string
+
+	// stateVkeyHex, messageVkeyHex, zkIsmNamespaceHex and sequencerPubKeyHex override the circuit parameters of
+	// deployed zk isms, the vkeys default to the testdata hashes and the pubkey to the one reported by ev-node.
+	stateVkeyHex       string
+	messageVkeyHex     string
+	zkIsmNamespaceHex  string
+	sequencerPubKeyHex string
+
+	// generateOnly writes the unsigned tx of the first broadcast to outputDocument, or stdout, instead of signing
+	// and broadcasting it.
+	generateOnly   bool
+	outputDocument string
+
+	// logLevel and logFormat configure the structured logs written to stderr.
+	logLevel  string
+	logFormat string
+
+	// output is the output format of the deploy and query commands, deploy commands print only the deployed
+	// HyperlaneConfig to stdout with json.
+	output string
+
+	// existingMailboxID is the mailbox deploy commands create the token against instead of a new mailbox and hook.
+	existingMailboxID string
+
+	// configOut overrides the path the deployed HyperlaneConfig is written to.
+	configOut string
+
+	// configFile is the deployment config whose values are applied to unset flags, deployFileConfig holds its
+	// decoded contents.
+	configFile       string
+	deployFileConfig *DeployFileConfig
+
+	// contextTimeout bounds the whole command, including every gRPC, ev-node and EVM RPC call it makes.
+	contextTimeout time.Duration
+)
+
+// ErrInconsistent is returned by commands checking on-chain state after reporting a mismatch, main exits with a
+// non-zero status without printing it again.
+var ErrInconsistent = errors.New("inconsistent state")
+
 type HyperlaneConfig struct {
-	IsmID     util.HexAddress `json:"ism_id"`
-	MailboxID util.HexAddress `json:"mailbox_id"`
-	HooksID   util.HexAddress `json:"hooks_id"`
-	TokenID   util.HexAddress `json:"collateral_token_id"`
+	IsmID     util.HexAddress  `json:"ism_id"`
+	MailboxID util.HexAddress  `json:"mailbox_id"`
+	HooksID   util.HexAddress  `json:"hooks_id"`
+	HookType  string           `json:"hook_type,omitempty"`
+	IgpID     *util.HexAddress `json:"igp_id,omitempty"`
+	TokenID   util.HexAddress  `json:"collateral_token_id"`
+
+	SyntheticTokenID *util.HexAddress `json:"synthetic_token_id,omitempty"`
+
+	// Build identifies the CLI build that last wrote the config.
+	Build *BuildInfo `json:"build,omitempty"`
 }
 
 func NewRootCmd() *cobra.Command {
@@ -31,165 +187,1765 @@ func NewRootCmd() *cobra.Command {
 		Short: "A CLI for deploying hyperlane cosmosnative infrastructure",
 		Long: `This CLI provides deployment functionality for hyperlane comosnative modules. 
 		It deploys basic core components and warp route collateral token for testing purposes.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if output != outputText && output != outputJSON {
+				return fmt.Errorf("unknown output format %q, expected %q or %q", output, outputText, outputJSON)
+			}
+
+			if contextTimeout < 0 {
+				return fmt.Errorf("--context-timeout must not be negative, got %s", contextTimeout)
+			}
+
+			if contextTimeout > 0 {
+				ctx, cancel := context.WithTimeout(cmd.Context(), contextTimeout)
+				cobra.OnFinalize(cancel)
+				cmd.SetContext(ctx)
+			}
+
+			if configFile != "" {
+				cfg, err := readDeployFileConfig(configFile)
+				if err != nil {
+					return err
+				}
+
+				if err := applyDeployFileConfig(cmd, cfg); err != nil {
+					return err
+				}
+				deployFileConfig = cfg
+			}
+
+			if err := checkConfirmation(cmd); err != nil {
+				return err
+			}
+
+			return setupLogger()
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
+		// errors are printed by main, usage is only printed on --help
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Version:       Version,
 	}
 
-	rootCmd.AddCommand(getDeployNoopIsmStackCmd())
-	rootCmd.AddCommand(getDeployZKIsmStackCmd())
-	rootCmd.AddCommand(getEnrollRouterCmd())
-	rootCmd.AddCommand(getSetupZkIsmCmd())
+	rootCmd.SetVersionTemplate(currentBuildInfo().String())
+
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "minimum level of logs written to stderr (debug, info, warn or error)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logFormatText, "format of logs written to stderr (text or json)")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "YAML or JSON deployment config providing values for flags not passed on the command line")
+	rootCmd.PersistentFlags().DurationVar(&contextTimeout, "context-timeout", 0, "maximum duration of the whole command, cancelling any pending RPC once exceeded (0 for no limit)")
+	rootCmd.PersistentFlags().IntVar(&batchSize, "batch-size", 0, "maximum number of msgs per transaction when broadcasting multiple msgs (0 for unlimited)")
+
+	rootCmd.PersistentFlags().BoolVar(&timing, "timing", false, "print the wall-clock time spent on each broadcast after deploying")
+
+	rootCmd.PersistentFlags().Uint64Var(&confirmations, "confirmations", 0, "number of blocks to wait for after tx inclusion")
+
+	rootCmd.PersistentFlags().BoolVar(&generateOnly, "generate-only", false, "write the unsigned tx with the signer account number and sequence instead of signing and broadcasting it")
+	rootCmd.PersistentFlags().StringVar(&outputDocument, "output-document", "", "file generated and signed txs are written to (defaults to stdout)")
+	rootCmd.PersistentFlags().StringVar(&broadcastMode, "broadcast-mode", broadcastModeBlock, "wait for tx confirmation (block), or return after CheckTx (sync) or submission (async); commands reading tx events require block")
+	rootCmd.PersistentFlags().StringVar(&confirmStrategy, "confirm", confirmPoll, "tx confirmation strategy in block broadcast mode (poll, event or async)")
+	rootCmd.PersistentFlags().DurationVar(&confirmTimeout, "timeout", broadcaster.DefaultConfirmTimeout, "maximum time to wait for a tx to be confirmed, or for the EVM and ev-node RPCs to respond before deploying zk isms")
+	rootCmd.PersistentFlags().DurationVar(&confirmInterval, "confirm-interval", broadcaster.DefaultConfirmInterval, "initial delay between tx confirmation polls, doubled after every poll")
+	rootCmd.PersistentFlags().DurationVar(&confirmMaxInterval, "confirm-max-interval", broadcaster.DefaultConfirmMaxInterval, "maximum delay between tx confirmation polls")
+	rootCmd.PersistentFlags().StringVar(&cometRPC, "comet-rpc", "http://celestia-validator:26657", "CometBFT RPC address used by the event confirmation strategy and to fetch celestia headers for zk isms")
+
+	rootCmd.PersistentFlags().BoolVar(&force, "force", false, "deploy a mailbox even if its local domain is already in use")
+	rootCmd.PersistentFlags().BoolVar(&skipBalanceCheck, "skip-balance-check", false, "deploy even if the signer balance cannot cover the estimated fees")
+
+	rootCmd.PersistentFlags().StringVar(&outputDir, "output-dir", ".", "directory generated artifacts are written to")
+	rootCmd.PersistentFlags().StringVar(&configOut, "config-out", "", "path the deployed config is written to (defaults to hyperlane-cosmosnative.json in --output-dir)")
+	rootCmd.PersistentFlags().StringVar(&output, "output", outputText, "output format (text or json), deploy commands print only the deployed config with json")
+
+	rootCmd.PersistentFlags().StringVar(&mnemonicFlag, "mnemonic", "", "signing mnemonic, overrides HYP_MNEMONIC")
+	rootCmd.PersistentFlags().StringVar(&mnemonicFile, "mnemonic-file", "", "path to a file containing the signing mnemonic, overrides HYP_MNEMONIC")
+	rootCmd.MarkFlagsMutuallyExclusive("mnemonic", "mnemonic-file")
+	rootCmd.PersistentFlags().StringVar(&keyringBackend, "keyring-backend", keyring.BackendMemory, "keyring backend (memory, file, os or test), memory derives the key from the mnemonic")
+	rootCmd.PersistentFlags().StringVar(&keyringDir, "keyring-dir", filepath.Join(userHomeDir(), ".celestia-app"), "keyring directory for non-memory backends")
+	rootCmd.PersistentFlags().BoolVar(&useLedger, "ledger", false, "sign transactions with a Ledger device (requires a build with -tags ledger)")
+	rootCmd.PersistentFlags().StringVar(&fromKey, "from", "", "name of the signing key in the keyring for non-memory backends")
+	rootCmd.PersistentFlags().Uint32Var(&hdCoinType, "coin-type", sdk.CoinType, "BIP44 coin type of the key derived from the mnemonic or Ledger device")
+	rootCmd.PersistentFlags().Uint32Var(&hdAccount, "account", 0, "BIP44 account of the key derived from the mnemonic or Ledger device")
+	rootCmd.PersistentFlags().Uint32Var(&hdIndex, "index", 0, "BIP44 address index of the key derived from the mnemonic or Ledger device")
+
+	rootCmd.PersistentFlags().StringVar(&gasSetting, "gas", "", "gas limit per tx, either an integer or auto to require simulation (simulates with a fallback by default)")
+	rootCmd.PersistentFlags().Float64Var(&gasAdjustment, "gas-adjustment", 1.3, "multiplier applied to the simulated gas used")
+	rootCmd.PersistentFlags().StringVar(&fees, "fees", "", "fixed fee paid per tx, e.g. 1000utia (defaults to 800utia)")
+	rootCmd.PersistentFlags().StringVar(&gasPrices, "gas-prices", "", "gas prices used to compute the fee from the gas limit, e.g. 0.025utia")
+	rootCmd.MarkFlagsMutuallyExclusive("fees", "gas-prices")
+	rootCmd.PersistentFlags().StringVar(&feeGranter, "fee-granter", "", "address paying tx fees, requires an on-chain feegrant allowance to the signer")
+	rootCmd.PersistentFlags().BoolVar(&grpcInsecure, "grpc-insecure", false, "connect to the gRPC endpoint without TLS")
+	rootCmd.PersistentFlags().IntVar(&grpcRetries, "grpc-retries", 3, "number of times account queries and broadcasts are retried after a gRPC transport failure")
+	rootCmd.PersistentFlags().DurationVar(&grpcRetryDelay, "grpc-retry-delay", broadcaster.DefaultRetryDelay, "delay before reconnecting and retrying a gRPC call after a transport failure")
+	rootCmd.PersistentFlags().StringVar(&grpcTLSCA, "grpc-tls-ca", "", "path to a PEM CA bundle used to verify the gRPC server (defaults to the system roots)")
+	rootCmd.PersistentFlags().StringVar(&grpcTLSCert, "grpc-tls-cert", "", "path to a PEM client certificate for mTLS")
+	rootCmd.PersistentFlags().StringVar(&grpcTLSKey, "grpc-tls-key", "", "path to a PEM client key for mTLS")
+	rootCmd.PersistentFlags().StringVar(&grpcSocket, "socket", "", "unix socket of a hyp daemon to forward gRPC calls through instead of dialing the celestia-grpc address")
+
+	rootCmd.AddCommand(requireConfirmation(getDeployCmd()))
+	rootCmd.AddCommand(getConfigCmd())
+	rootCmd.AddCommand(requireConfirmation(getDeployNoopIsmStackCmd()))
+	rootCmd.AddCommand(requireConfirmation(getDeployZKIsmStackCmd()))
+	rootCmd.AddCommand(requireConfirmation(getEnrollRouterCmd()))
+	rootCmd.AddCommand(requireConfirmation(getEnrollFromConfigCmd()))
+	rootCmd.AddCommand(requireConfirmation(getSetupZkIsmCmd()))
+	rootCmd.AddCommand(getCheckMultisigCmd())
+	rootCmd.AddCommand(getTeardownCmd())
+	rootCmd.AddCommand(getSignMessageCmd())
+	rootCmd.AddCommand(getVerifyMessageCmd())
+	rootCmd.AddCommand(requireConfirmation(getStressDeployCmd()))
+	rootCmd.AddCommand(getVerifyZKProofCmd())
+	rootCmd.AddCommand(getCheckRootConsistencyCmd())
+	rootCmd.AddCommand(requireConfirmation(getUpdateZKIsmCmd()))
+	rootCmd.AddCommand(getAccountInfoCmd())
+	rootCmd.AddCommand(getBenchBroadcastCmd())
+	rootCmd.AddCommand(getMigrateHooksCmd())
+	rootCmd.AddCommand(getAnnounceValidatorCmd())
+	rootCmd.AddCommand(getCheckIsmConsistencyCmd())
+	rootCmd.AddCommand(getConfigToEVMCmd())
+	rootCmd.AddCommand(getQueryCmd())
+	rootCmd.AddCommand(requireConfirmation(getDeployIgpCmd()))
+	rootCmd.AddCommand(requireConfirmation(getDeployRoutingIsmCmd()))
+	rootCmd.AddCommand(getVersionCmd())
+	rootCmd.AddCommand(requireConfirmation(getClaimIgpCmd()))
+	rootCmd.AddCommand(requireConfirmation(getDeploySyntheticCmd()))
+	rootCmd.AddCommand(requireConfirmation(getTransferCmd()))
+	rootCmd.AddCommand(getAnnounceValidatorsCmd())
+	rootCmd.AddCommand(requireConfirmation(getTransferMailboxOwnershipCmd()))
+	rootCmd.AddCommand(requireConfirmation(getTransferTokenOwnershipCmd()))
+	rootCmd.AddCommand(getSignTxCmd())
+	rootCmd.AddCommand(getBroadcastTxCmd())
+	rootCmd.AddCommand(getDaemonCmd())
 	return rootCmd
 }
 
+func userHomeDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return home
+}
+
+// addStackFlags registers the flags configuring the mailbox, hook and collateral token deployed by the provided
+// command.
+func addStackFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&collateralDenom, "collateral-denom", denom, "origin denom of the deployed collateral token")
+	cmd.Flags().StringVar(&hookType, "hook-type", hookTypeNoop, "hook deployed as the default and required hook of new mailboxes (noop, merkle or igp)")
+	cmd.Flags().StringVar(&igpBeneficiary, "igp-beneficiary", "", "owner of the deployed igp who can claim its fees (defaults to the signer)")
+	cmd.Flags().StringSliceVar(&igpGasConfigs, "igp-gas-config", nil, "destination gas config of the deployed igp as <remote-domain>:<gas-overhead>:<token-exchange-rate>:<gas-price>")
+	addReuseExistingFlag(cmd)
+}
+
+// addReuseExistingFlag registers --reuse-existing on the provided deploy command.
+func addReuseExistingFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&reuseExisting, "reuse-existing", false, "reuse equivalent existing components owned by the signer instead of creating duplicates")
+}
+
+// addZKIsmFlags registers the flags configuring the zk ism deployed by the provided command.
+func addZKIsmFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&groth16VkeyPath, "groth16-vkey", "", "path to the groth16 verifying key (defaults to "+defaultGroth16VkeyPath+" if present)")
+	cmd.Flags().StringVar(&stateVkeyHex, "state-vkey", "", "32-byte hex state transition program vkey hash (defaults to the testdata hash)")
+	cmd.Flags().StringVar(&messageVkeyHex, "message-vkey", "", "32-byte hex state membership program vkey hash (defaults to the testdata hash)")
+	cmd.Flags().StringVar(&zkIsmNamespaceHex, "namespace", namespaceHex, "29-byte hex celestia namespace of the rollup blobs")
+	cmd.Flags().StringVar(&sequencerPubKeyHex, "sequencer-pubkey", "", "32-byte hex sequencer public key (defaults to the key reported by ev-node)")
+}
+
 func getDeployZKIsmStackCmd() *cobra.Command {
+	var domain uint32
+
 	deployCmd := &cobra.Command{
 		Use:   "deploy-zkism [celestia-grpc] [evm-rpc] [ev-node-rpc]",
 		Short: "Deploy cosmosnative hyperlane components using a ZKExecutionIsm to a remote service via gRPC",
 		Args:  cobra.ExactArgs(3),
-		Run: func(cmd *cobra.Command, args []string) {
-			ctx := cmd.Context()
-			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDeployZKIsm(cmd.Context(), args[0], args[1], args[2], domain)
+		},
+	}
 
-			grpcAddr := args[0]
-			grpcConn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-			if err != nil {
-				log.Fatalf("failed to connect to gRPC: %v", err)
-			}
-			defer grpcConn.Close()
+	addZKIsmFlags(deployCmd)
+	addStackFlags(deployCmd)
+	deployCmd.Flags().Uint32Var(&domain, "local-domain", defaultLocalDomain, "hyperlane domain of the deployed mailbox")
+	deployCmd.Flags().StringVar(&existingMailboxID, "mailbox-id", "", "existing mailbox to create the token against, skipping mailbox and hook creation")
+	return deployCmd
+}
 
-			broadcaster := NewBroadcaster(enc, grpcConn)
+// runDeployZKIsm deploys a ZKExecutionISM and the core and warp components using it on the provided local domain.
+func runDeployZKIsm(ctx context.Context, grpcAddr, evmRpcAddr, evnodeRpcAddr string, domain uint32) error {
+	enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
 
-			evmRpcAddr := args[1]
-			client, err := ethclient.Dial(fmt.Sprintf("http://%s", evmRpcAddr))
-			if err != nil {
-				log.Fatal(err)
-			}
+	if err := validateLocalDomain(domain); err != nil {
+		return err
+	}
 
-			evnodeRpcAddr := args[2]
-			evnode := evclient.NewClient(fmt.Sprintf("http://%s", evnodeRpcAddr))
+	params, err := zkIsmParamsFromFlags()
+	if err != nil {
+		return err
+	}
 
-			ismID := SetupZKIsm(ctx, broadcaster, client, evnode)
-			SetupWithIsm(ctx, broadcaster, ismID)
-		},
+	client, evnode, err := dialExecutionClients(ctx, evmRpcAddr, evnodeRpcAddr)
+	if err != nil {
+		return err
 	}
-	return deployCmd
+
+	grpcConn, err := dialGRPC(grpcAddr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to gRPC: %w", err)
+	}
+	defer grpcConn.Close()
+
+	broadcaster, err := NewBroadcaster(enc, grpcConn)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidateDenom(ctx, banktypes.NewQueryClient(grpcConn), collateralDenom); err != nil {
+		return err
+	}
+
+	if existingMailboxID != "" {
+		if _, err := queryExistingMailbox(ctx, broadcaster); err != nil {
+			return err
+		}
+	}
+
+	if err := checkSignerBalance(ctx, broadcaster, deployTxCount()); err != nil {
+		return err
+	}
+
+	ismID, err := SetupZKIsm(ctx, broadcaster, client, evnode, params)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := SetupWithIsm(ctx, broadcaster, ismID, domain, collateralDenom)
+	if err != nil {
+		return err
+	}
+
+	if err := writeConfig(cfg); err != nil {
+		return err
+	}
+
+	if timing {
+		broadcaster.PrintTimings()
+	}
+
+	return nil
 }
 
 func getDeployNoopIsmStackCmd() *cobra.Command {
+	var (
+		batched bool
+		domain  uint32
+	)
+
 	deployCmd := &cobra.Command{
 		Use:   "deploy-noopism [celestia-grpc]",
 		Short: "Deploy cosmosnative hyperlane components using a NoopIsm to a remote service via gRPC",
 		Args:  cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			ctx := cmd.Context()
-			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDeployNoopIsm(cmd.Context(), args[0], domain, batched)
+		},
+	}
 
-			grpcAddr := args[0]
-			grpcConn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-			if err != nil {
-				log.Fatalf("failed to connect to gRPC: %v", err)
-			}
-			defer grpcConn.Close()
+	addStackFlags(deployCmd)
+	deployCmd.Flags().Uint32Var(&domain, "local-domain", defaultLocalDomain, "hyperlane domain of the deployed mailbox")
+	deployCmd.Flags().BoolVar(&batched, "batch", false, "create the NoopISM and NoopHook in a single tx, the remaining msgs depend on ids from prior txs and are not combined")
+	deployCmd.Flags().StringVar(&existingMailboxID, "mailbox-id", "", "existing mailbox to create the token against, skipping mailbox and hook creation")
+	return deployCmd
+}
 
-			broadcaster := NewBroadcaster(enc, grpcConn)
-			msgCreateNoopISM := ismtypes.MsgCreateNoopIsm{
-				Creator: broadcaster.address.String(),
-			}
+// runDeployNoopIsm deploys a NoopISM and the core and warp components using it on the provided local domain.
+func runDeployNoopIsm(ctx context.Context, grpcAddr string, domain uint32, batched bool) error {
+	enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+	grpcConn, err := dialGRPC(grpcAddr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to gRPC: %w", err)
+	}
+	defer grpcConn.Close()
 
-			res := broadcaster.BroadcastTx(ctx, &msgCreateNoopISM)
-			ismID := parseIsmIDFromNoopISMEvents(res.Events)
+	broadcaster, err := NewBroadcaster(enc, grpcConn)
+	if err != nil {
+		return err
+	}
 
-			SetupWithIsm(ctx, broadcaster, ismID)
-		},
+	if err := ValidateDenom(ctx, banktypes.NewQueryClient(grpcConn), collateralDenom); err != nil {
+		return err
 	}
-	return deployCmd
+
+	if existingMailboxID != "" {
+		if _, err := queryExistingMailbox(ctx, broadcaster); err != nil {
+			return err
+		}
+	}
+
+	if err := checkSignerBalance(ctx, broadcaster, deployTxCount()); err != nil {
+		return err
+	}
+
+	if err := validateLocalDomain(domain); err != nil {
+		return err
+	}
+
+	if batched && existingMailboxID != "" {
+		return fmt.Errorf("--batch cannot be used with --mailbox-id")
+	}
+
+	var cfg *HyperlaneConfig
+	if batched {
+		cfg, err = SetupNoopStackBatched(ctx, broadcaster, domain, collateralDenom)
+	} else {
+		var ismID util.HexAddress
+		if ismID, err = setupNoopIsm(ctx, broadcaster); err == nil {
+			cfg, err = SetupWithIsm(ctx, broadcaster, ismID, domain, collateralDenom)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writeConfig(cfg); err != nil {
+		return err
+	}
+
+	if timing {
+		broadcaster.PrintTimings()
+	}
+
+	return nil
 }
 
 func getEnrollRouterCmd() *cobra.Command {
+	var remoteGas string
+
 	enrollRouterCmd := &cobra.Command{
 		Use:   "enroll-remote-router [grpc-addr] [token-id] [remote-domain] [remote-contract]",
 		Short: "Enroll the remote router contract address for a cosmosnative hyperlane warp route",
 		Args:  cobra.ExactArgs(4),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
 
 			grpcAddr := args[0]
-			grpcConn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			grpcConn, err := dialGRPC(grpcAddr)
 			if err != nil {
-				log.Fatalf("failed to connect to gRPC: %v", err)
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
 			}
 			defer grpcConn.Close()
 
-			broadcaster := NewBroadcaster(enc, grpcConn)
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
 
 			tokenID, err := util.DecodeHexAddress(args[1])
 			if err != nil {
-				log.Fatalf("failed to parse token id: %v", err)
+				return fmt.Errorf("failed to parse token id: %w", err)
 			}
 
 			domain, err := strconv.ParseUint(args[2], 10, 32)
 			if err != nil {
-				log.Fatalf("failed to parse remote domain: %v", err)
+				return fmt.Errorf("failed to parse remote domain: %w", err)
 			}
 
 			receiverContract := args[3]
 
-			SetupRemoteRouter(ctx, broadcaster, tokenID, uint32(domain), receiverContract)
+			gas, ok := math.NewIntFromString(remoteGas)
+			if !ok || gas.IsNegative() {
+				return fmt.Errorf("invalid remote gas %q, expected a non-negative integer", remoteGas)
+			}
+
+			return SetupRemoteRouter(ctx, broadcaster, tokenID, uint32(domain), receiverContract, gas)
 		},
 	}
+
+	enrollRouterCmd.Flags().StringVar(&remoteGas, "remote-gas", "0", "gas paid for handling messages on the remote domain, required for EVM destinations")
 	return enrollRouterCmd
 }
 
 func getSetupZkIsmCmd() *cobra.Command {
+	var configPath string
+
 	deployCmd := &cobra.Command{
 		Use:   "setup-zkism [celestia-grpc] [evm-rpc] [ev-node-rpc]",
 		Short: "Deploy a new zk ism and configure it with an existing stack",
 		Args:  cobra.ExactArgs(3),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
 
+			params, err := zkIsmParamsFromFlags()
+			if err != nil {
+				return err
+			}
+
+			var existing *HyperlaneConfig
+			if configPath != "" {
+				if existing, err = readConfig(configPath); err != nil {
+					return err
+				}
+			}
+
+			client, evnode, err := dialExecutionClients(ctx, args[1], args[2])
+			if err != nil {
+				return err
+			}
+
 			grpcAddr := args[0]
-			grpcConn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			grpcConn, err := dialGRPC(grpcAddr)
 			if err != nil {
-				log.Fatalf("failed to connect to gRPC: %v", err)
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
 			}
 			defer grpcConn.Close()
 
-			broadcaster := NewBroadcaster(enc, grpcConn)
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
 
-			evmRpcAddr := args[1]
-			client, err := ethclient.Dial(fmt.Sprintf("http://%s", evmRpcAddr))
+			// resolve the stack before creating the ism so that a bad config does not leave an unused ism behind
+			mailbox, token, err := resolveStack(ctx, coretypes.NewQueryClient(grpcConn), warptypes.NewQueryClient(grpcConn), existing)
+			if err != nil {
+				return err
+			}
+
+			ismID, err := SetupZKIsm(ctx, broadcaster, client, evnode, params)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := OverwriteIsm(ctx, broadcaster, ismID, mailbox, token)
+			if err != nil {
+				return err
+			}
+
+			// keep the components recorded in the loaded config that the new ism does not replace
+			if existing != nil {
+				existing.IsmID = cfg.IsmID
+				cfg = existing
+			}
+
+			if err := writeConfig(cfg); err != nil {
+				return err
+			}
+
+			if timing {
+				broadcaster.PrintTimings()
+			}
+
+			return nil
+		},
+	}
+
+	deployCmd.Flags().StringVar(&configPath, "config", "", "path to a hyperlane config written on deployment whose mailbox and token are updated (defaults to the first mailbox and token on chain)")
+	addZKIsmFlags(deployCmd)
+	return deployCmd
+}
+
+func getCheckMultisigCmd() *cobra.Command {
+	var mailboxIDFlag string
+
+	checkCmd := &cobra.Command{
+		Use:   "check-multisig [celestia-grpc] [ism-id]",
+		Short: "Check a MerkleRootMultisigIsm's validator set against announced validator storage locations",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			ismID, err := util.DecodeHexAddress(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to parse ism id: %w", err)
+			}
+
+			var mailboxID util.HexAddress
+			if mailboxIDFlag != "" {
+				if mailboxID, err = util.DecodeHexAddress(mailboxIDFlag); err != nil {
+					return fmt.Errorf("failed to parse mailbox id: %w", err)
+				}
+			} else {
+				hypQueryClient := coretypes.NewQueryClient(grpcConn)
+				mailboxResp, err := hypQueryClient.Mailboxes(ctx, &coretypes.QueryMailboxesRequest{})
+				if err != nil {
+					return err
+				}
+
+				switch len(mailboxResp.Mailboxes) {
+				case 0:
+					return fmt.Errorf("no mailboxes found, validators announce storage locations against a mailbox")
+				case 1:
+					mailboxID = mailboxResp.Mailboxes[0].Id
+				default:
+					return fmt.Errorf("found %d mailboxes, use --mailbox-id to select the mailbox validators announced on", len(mailboxResp.Mailboxes))
+				}
+			}
+
+			return CheckMultisig(ctx, enc, ismtypes.NewQueryClient(grpcConn), ismID, mailboxID)
+		},
+	}
+
+	checkCmd.Flags().StringVar(&mailboxIDFlag, "mailbox-id", "", "mailbox the validators announced their storage locations on (defaults to the only mailbox on chain)")
+	return checkCmd
+}
+
+func getTeardownCmd() *cobra.Command {
+	var configPath string
+
+	teardownCmd := &cobra.Command{
+		Use:   "teardown [celestia-grpc]",
+		Short: "Tear down a deployment by unenrolling the remote routers of a saved hyperlane config",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+			if configPath == "" {
+				configPath = configOutputPath()
+			}
+
+			cfg, err := readConfig(configPath)
+			if err != nil {
+				return err
+			}
+
+			return Teardown(ctx, broadcaster, warptypes.NewQueryClient(grpcConn), cfg)
+		},
+	}
+
+	teardownCmd.Flags().StringVar(&configPath, "config", "", "path to the hyperlane config written on deployment (defaults to the config in --output-dir)")
+	return teardownCmd
+}
+
+func getSignMessageCmd() *cobra.Command {
+	signCmd := &cobra.Command{
+		Use:   "sign-message [text]",
+		Short: "Sign an arbitrary message with the deployer key as an ADR-036 off-chain signature",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+			kr, keyName, signer, err := newKeyring(enc)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 
-			evnodeRpcAddr := args[2]
-			evnode := evclient.NewClient(fmt.Sprintf("http://%s", evnodeRpcAddr))
+			sig, pubKey, err := SignMessage(kr, keyName, signer, []byte(args[0]))
+			if err != nil {
+				return err
+			}
 
-			ismID := SetupZKIsm(ctx, broadcaster, client, evnode)
+			fmt.Printf("address: %s\n", signer)
+			fmt.Printf("pubkey: %s\n", hex.EncodeToString(pubKey))
+			fmt.Printf("signature: %s\n", base64.StdEncoding.EncodeToString(sig))
+
+			return nil
+		},
+	}
+	return signCmd
+}
 
-			hypQueryClient := coretypes.NewQueryClient(grpcConn)
-			mailboxResp, err := hypQueryClient.Mailboxes(ctx, &coretypes.QueryMailboxesRequest{})
+func getVerifyMessageCmd() *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use:   "verify-message [address] [pubkey-hex] [signature-base64] [text]",
+		Short: "Verify an ADR-036 off-chain signature against the signer address",
+		Args:  cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			signer, err := sdk.AccAddressFromBech32(args[0])
 			if err != nil {
-				log.Fatal(err)
+				return fmt.Errorf("failed to parse address: %w", err)
 			}
 
-			mailbox := mailboxResp.Mailboxes[0]
+			pubKey, err := hex.DecodeString(strings.TrimPrefix(args[1], "0x"))
+			if err != nil {
+				return fmt.Errorf("failed to decode pubkey: %w", err)
+			}
 
-			warpQueryClient := warptypes.NewQueryClient(grpcConn)
-			tokenResp, err := warpQueryClient.Tokens(ctx, &warptypes.QueryTokensRequest{})
+			sig, err := base64.StdEncoding.DecodeString(args[2])
 			if err != nil {
-				log.Fatal(err)
+				return fmt.Errorf("failed to decode signature: %w", err)
+			}
+
+			if err := VerifyMessage(signer, pubKey, sig, []byte(args[3])); err != nil {
+				return fmt.Errorf("signature verification failed: %w", err)
 			}
 
-			token := tokenResp.Tokens[0]
+			fmt.Printf("signature is valid for %s\n", signer)
 
-			OverwriteIsm(ctx, broadcaster, ismID, mailbox, token)
+			return nil
 		},
 	}
-	return deployCmd
+	return verifyCmd
+}
+
+func getStressDeployCmd() *cobra.Command {
+	var (
+		count, concurrency int
+		domainBase         uint32
+	)
+
+	stressCmd := &cobra.Command{
+		Use:   "stress-deploy [celestia-grpc]",
+		Short: "Concurrently deploy many NoopISM stacks to stress test the cosmosnative hyperlane modules",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			if count <= 0 || concurrency <= 0 {
+				return fmt.Errorf("--count and --concurrency must be positive")
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			if err := ValidateDenom(ctx, banktypes.NewQueryClient(grpcConn), collateralDenom); err != nil {
+				return err
+			}
+
+			if domainBase == 0 {
+				if domainBase, err = nextFreeLocalDomain(ctx, coretypes.NewQueryClient(grpcConn)); err != nil {
+					return err
+				}
+			}
+
+			if uint64(domainBase)+uint64(count-1) > uint64(^uint32(0)) {
+				return fmt.Errorf("--domain-base %d leaves no room for %d local domains", domainBase, count)
+			}
+
+			slog.Info("deploying stacks on consecutive local domains", "first", domainBase, "last", domainBase+uint32(count-1))
+
+			err = StressDeploy(ctx, broadcaster, count, concurrency, domainBase)
+
+			if timing {
+				broadcaster.PrintTimings()
+			}
+
+			return err
+		},
+	}
+
+	stressCmd.Flags().IntVar(&count, "count", 10, "number of NoopISM stacks to deploy")
+	stressCmd.Flags().IntVar(&concurrency, "concurrency", 1, "number of deployments to run concurrently")
+	addStackFlags(stressCmd)
+	stressCmd.Flags().Uint32Var(&domainBase, "domain-base", 0, "local domain of the first stack, each further stack uses the next domain (defaults to the domain after the highest one in use)")
+	return stressCmd
+}
+
+func getVerifyZKProofCmd() *cobra.Command {
+	var kind string
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify-zk-proof [celestia-grpc] [ism-id] [proof-file] [public-values-file]",
+		Short: "Verify a groth16 proof locally against the verifying keys of a zk execution ism",
+		Args:  cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			ismID, err := util.DecodeHexAddress(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to parse ism id: %w", err)
+			}
+
+			proof, err := readBytesFile(args[2])
+			if err != nil {
+				return err
+			}
+
+			publicValues, err := readBytesFile(args[3])
+			if err != nil {
+				return err
+			}
+
+			if err := VerifyZKProof(ctx, zkismtypes.NewQueryClient(grpcConn), ismID, kind, proof, publicValues); err != nil {
+				return fmt.Errorf("proof verification failed: %w", err)
+			}
+
+			fmt.Printf("successfully verified %s proof against ism %s\n", kind, ismID)
+
+			return nil
+		},
+	}
+
+	verifyCmd.Flags().StringVar(&kind, "kind", proofKindStateTransition, "program the proof was generated for (state-transition or state-membership)")
+	return verifyCmd
+}
+
+func getUpdateZKIsmCmd() *cobra.Command {
+	var proofPath, publicValuesPath string
+
+	updateCmd := &cobra.Command{
+		Use:   "update-zkism [celestia-grpc] [ism-id] [evm-rpc] [ev-node-rpc]",
+		Short: "Advance the trusted state of a zk execution ism to the latest EVM block included on Celestia",
+		Args:  cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			ismID, err := util.DecodeHexAddress(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to parse ism id: %w", err)
+			}
+
+			proof, err := readBytesFile(proofPath)
+			if err != nil {
+				return err
+			}
+
+			publicValues, err := readBytesFile(publicValuesPath)
+			if err != nil {
+				return err
+			}
+
+			client, evnode, err := dialExecutionClients(ctx, args[2], args[3])
+			if err != nil {
+				return err
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			return UpdateZKIsm(ctx, broadcaster, client, evnode, ismID, proof, publicValues)
+		},
+	}
+
+	updateCmd.Flags().StringVar(&proofPath, "proof", "", "path to the groth16 state transition proof")
+	updateCmd.Flags().StringVar(&publicValuesPath, "public-values", "", "path to the public values of the state transition proof")
+	_ = updateCmd.MarkFlagRequired("proof")
+	_ = updateCmd.MarkFlagRequired("public-values")
+	return updateCmd
+}
+
+func getCheckRootConsistencyCmd() *cobra.Command {
+	checkCmd := &cobra.Command{
+		Use:   "check-root-consistency [evm-rpc] [celestia-grpc] [ism-id] [block]",
+		Short: "Compare the EVM state root at a block against the trusted state root of a zk execution ism",
+		Args:  cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			evmRpcAddr := args[0]
+			client, err := ethclient.Dial(fmt.Sprintf("http://%s", evmRpcAddr))
+			if err != nil {
+				return err
+			}
+
+			grpcAddr := args[1]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			ismID, err := util.DecodeHexAddress(args[2])
+			if err != nil {
+				return fmt.Errorf("failed to parse ism id: %w", err)
+			}
+
+			height, err := strconv.ParseUint(args[3], 10, 64)
+			if err != nil {
+				return fmt.Errorf("failed to parse block height: %w", err)
+			}
+
+			consistent, err := CheckRootConsistency(ctx, client, zkismtypes.NewQueryClient(grpcConn), ismID, height)
+			if err != nil {
+				return err
+			}
+
+			if !consistent {
+				return ErrInconsistent
+			}
+
+			return nil
+		},
+	}
+	return checkCmd
+}
+
+func getAccountInfoCmd() *cobra.Command {
+	accountCmd := &cobra.Command{
+		Use:   "account-info [celestia-grpc] [address]",
+		Short: "Query the account number and current sequence of an account",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			acc, err := broadcaster.QueryAccount(ctx, enc, authtypes.NewQueryClient(grpcConn), args[1])
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("address: %s\n", acc.Address)
+			fmt.Printf("account number: %d\n", acc.AccountNumber)
+			fmt.Printf("sequence: %d\n", acc.Sequence)
+
+			return nil
+		},
+	}
+	return accountCmd
+}
+
+func getBenchBroadcastCmd() *cobra.Command {
+	var count int
+
+	benchCmd := &cobra.Command{
+		Use:   "bench-broadcast [celestia-grpc]",
+		Short: "Benchmark transaction submission and confirmation throughput using bank sends to self",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			if count <= 0 {
+				return fmt.Errorf("--count must be positive")
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			return BenchBroadcast(ctx, broadcaster, count)
+		},
+	}
+
+	benchCmd.Flags().IntVar(&count, "count", 100, "number of transactions to submit")
+	return benchCmd
+}
+
+func getMigrateHooksCmd() *cobra.Command {
+	var (
+		toHook string
+		all    bool
+	)
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate-hooks [celestia-grpc] [mailbox-ids...]",
+		Short: "Set the default and required hook on many mailboxes at once",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			hookID, err := util.DecodeHexAddress(toHook)
+			if err != nil {
+				return fmt.Errorf("failed to parse hook id: %w", err)
+			}
+
+			var mailboxIDs []util.HexAddress
+			if all {
+				hypQueryClient := coretypes.NewQueryClient(grpcConn)
+				mailboxResp, err := hypQueryClient.Mailboxes(ctx, &coretypes.QueryMailboxesRequest{})
+				if err != nil {
+					return err
+				}
+
+				for _, mailbox := range mailboxResp.Mailboxes {
+					mailboxIDs = append(mailboxIDs, mailbox.Id)
+				}
+			}
+
+			for _, arg := range args[1:] {
+				mailboxID, err := util.DecodeHexAddress(arg)
+				if err != nil {
+					return fmt.Errorf("failed to parse mailbox id: %w", err)
+				}
+
+				mailboxIDs = append(mailboxIDs, mailboxID)
+			}
+
+			if len(mailboxIDs) == 0 {
+				return fmt.Errorf("no mailboxes to migrate, provide mailbox ids or --all")
+			}
+
+			return MigrateHooks(ctx, broadcaster, hookID, mailboxIDs)
+		},
+	}
+
+	migrateCmd.Flags().StringVar(&toHook, "to-hook", "", "hook id to set as the default and required hook")
+	migrateCmd.Flags().BoolVar(&all, "all", false, "migrate all mailboxes")
+	_ = migrateCmd.MarkFlagRequired("to-hook")
+	return migrateCmd
+}
+
+func getAnnounceValidatorCmd() *cobra.Command {
+	announceCmd := &cobra.Command{
+		Use:   "announce-validator [celestia-grpc] [validator] [storage-location] [signature] [mailbox-id]",
+		Short: "Announce the signature storage location of a multisig validator",
+		Args:  cobra.ExactArgs(5),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			mailboxID, err := util.DecodeHexAddress(args[4])
+			if err != nil {
+				return fmt.Errorf("failed to parse mailbox id: %w", err)
+			}
+
+			return AnnounceValidator(ctx, broadcaster, args[1], args[2], args[3], mailboxID)
+		},
+	}
+	return announceCmd
+}
+
+func getAnnounceValidatorsCmd() *cobra.Command {
+	var (
+		batched     bool
+		concurrency int
+	)
+
+	announceCmd := &cobra.Command{
+		Use:   "announce-validators [celestia-grpc] [file]",
+		Short: "Announce the signature storage locations of multisig validators read from a JSON or CSV file",
+		Long: `Announce the signature storage locations of multisig validators read from a JSON array of
+{validator, storage_location, signature, mailbox_id} objects, or a .csv file with those columns.
+Every entry is attempted and a per-entry report is printed at the end.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			if concurrency <= 0 {
+				return fmt.Errorf("--concurrency must be positive")
+			}
+
+			announcements, err := readAnnouncements(args[1])
+			if err != nil {
+				return err
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			return AnnounceValidators(ctx, broadcaster, announcements, batched, concurrency)
+		},
+	}
+
+	announceCmd.Flags().BoolVar(&batched, "batch", false, "announce in as few txs as --batch-size allows instead of one tx per validator")
+	announceCmd.Flags().IntVar(&concurrency, "concurrency", 1, "number of announcement txs submitted in parallel")
+	return announceCmd
+}
+
+func getEnrollFromConfigCmd() *cobra.Command {
+	var concurrency int
+
+	enrollCmd := &cobra.Command{
+		Use:   "enroll-from-config [celestia-grpc] [routers-file]",
+		Short: "Enroll the remote routers of several tokens read from a JSON file",
+		Long: `Enroll the remote routers read from a JSON object mapping token ids, or the origin denom of a token
+owned by the signer, to a list of {remote_domain, remote_contract, gas} objects. Every enrollment is attempted,
+confirmed by re-querying the remote routers of its token and reported at the end.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			if concurrency <= 0 {
+				return fmt.Errorf("--concurrency must be positive")
+			}
+
+			routers, err := readRouterEnrollments(args[1])
+			if err != nil {
+				return err
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			return EnrollRoutersFromConfig(ctx, broadcaster, routers, concurrency)
+		},
+	}
+
+	enrollCmd.Flags().IntVar(&concurrency, "concurrency", 1, "number of enrollment txs submitted in parallel")
+	return enrollCmd
+}
+
+func getCheckIsmConsistencyCmd() *cobra.Command {
+	checkCmd := &cobra.Command{
+		Use:   "check-ism-consistency [celestia-grpc] [mailbox-id] [token-id]",
+		Short: "Check that a token's ism matches the default ism of its mailbox",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			mailboxID, err := util.DecodeHexAddress(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to parse mailbox id: %w", err)
+			}
+
+			tokenID, err := util.DecodeHexAddress(args[2])
+			if err != nil {
+				return fmt.Errorf("failed to parse token id: %w", err)
+			}
+
+			consistent, err := CheckIsmConsistency(ctx, coretypes.NewQueryClient(grpcConn), warptypes.NewQueryClient(grpcConn), mailboxID, tokenID)
+			if err != nil {
+				return err
+			}
+
+			if !consistent {
+				return ErrInconsistent
+			}
+
+			return nil
+		},
+	}
+	return checkCmd
+}
+
+func getConfigToEVMCmd() *cobra.Command {
+	var configPath string
+
+	configCmd := &cobra.Command{
+		Use:   "config-to-evm",
+		Short: "Print the EVM address form of each identifier in a saved hyperlane config",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if configPath == "" {
+				configPath = configOutputPath()
+			}
+
+			cfg, err := readConfig(configPath)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("ism_id:              %s\n", evmAddress(cfg.IsmID))
+			fmt.Printf("mailbox_id:          %s\n", evmAddress(cfg.MailboxID))
+			fmt.Printf("hooks_id:            %s\n", evmAddress(cfg.HooksID))
+			if cfg.IgpID != nil {
+				fmt.Printf("igp_id:              %s\n", evmAddress(*cfg.IgpID))
+			}
+
+			// the router enrolled on the EVM side is the collateral token, or the synthetic token of configs
+			// written by deploy-synthetic without a collateral token
+			var router *util.HexAddress
+			if cfg.TokenID != (util.HexAddress{}) {
+				fmt.Printf("collateral_token_id: %s\n", evmAddress(cfg.TokenID))
+				router = &cfg.TokenID
+			}
+			if cfg.SyntheticTokenID != nil {
+				fmt.Printf("synthetic_token_id:  %s\n", evmAddress(*cfg.SyntheticTokenID))
+				if router == nil {
+					router = cfg.SyntheticTokenID
+				}
+			}
+			if router != nil {
+				fmt.Printf("router:              %s\n", evmAddress(*router))
+			}
+
+			return nil
+		},
+	}
+
+	configCmd.Flags().StringVar(&configPath, "config", "", "path to the hyperlane config written on deployment (defaults to the config in --output-dir)")
+	return configCmd
+}
+
+func getQueryCmd() *cobra.Command {
+	queryCmd := &cobra.Command{
+		Use:   "query",
+		Short: "Query deployed cosmosnative hyperlane components",
+	}
+
+	queryCmd.AddCommand(getQueryMailboxesCmd())
+	queryCmd.AddCommand(getQueryTokensCmd())
+	queryCmd.AddCommand(getQueryIsmsCmd())
+	queryCmd.AddCommand(getQueryZKIsmCmd())
+	return queryCmd
+}
+
+func getQueryMailboxesCmd() *cobra.Command {
+	mailboxesCmd := &cobra.Command{
+		Use:   "mailboxes [celestia-grpc]",
+		Short: "List all mailboxes with their isms, hooks, local domain and message counts",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			mailboxes, err := QueryMailboxes(ctx, coretypes.NewQueryClient(grpcConn))
+			if err != nil {
+				return err
+			}
+
+			return PrintMailboxes(mailboxes, output)
+		},
+	}
+	return mailboxesCmd
+}
+
+func getQueryTokensCmd() *cobra.Command {
+	var tokenID string
+
+	tokensCmd := &cobra.Command{
+		Use:   "tokens [celestia-grpc]",
+		Short: "List warp tokens with their type, origin, owner and enrolled remote routers",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			tokens, err := QueryTokens(ctx, warptypes.NewQueryClient(grpcConn), tokenID)
+			if err != nil {
+				return err
+			}
+
+			return PrintTokens(tokens, output)
+		},
+	}
+
+	tokensCmd.Flags().StringVar(&tokenID, "token-id", "", "only show the token with this id")
+	return tokensCmd
+}
+
+func getQueryIsmsCmd() *cobra.Command {
+	var ismID string
+
+	ismsCmd := &cobra.Command{
+		Use:   "isms [celestia-grpc]",
+		Short: "List deployed isms with their type specific configuration",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			isms, err := QueryIsms(ctx, enc, ismtypes.NewQueryClient(grpcConn), zkismtypes.NewQueryClient(grpcConn), ismID)
+			if err != nil {
+				return err
+			}
+
+			return PrintIsms(isms, output)
+		},
+	}
+
+	ismsCmd.Flags().StringVar(&ismID, "ism-id", "", "only show the ism with this id")
+	return ismsCmd
+}
+
+func getQueryZKIsmCmd() *cobra.Command {
+	zkismCmd := &cobra.Command{
+		Use:   "zkism [celestia-grpc] [ism-id]",
+		Short: "Show the vkey hashes, namespace, sequencer pubkey and trusted state of a zk execution ism",
+		Long: `Show the vkey hashes, namespace, sequencer pubkey and trusted state of a zk execution ism. With
+--output json a single object is printed, so the vkeys can be diffed against those used by the prover.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			ism, err := QueryZKIsm(ctx, zkismtypes.NewQueryClient(grpcConn), args[1])
+			if err != nil {
+				return err
+			}
+
+			if output == outputJSON {
+				return printJSON(ism)
+			}
+
+			return PrintIsms([]ismInfo{ism}, output)
+		},
+	}
+	return zkismCmd
+}
+
+func getDeployRoutingIsmCmd() *cobra.Command {
+	deployCmd := &cobra.Command{
+		Use:   "deploy-routing-ism [celestia-grpc] [routes-file]",
+		Short: "Deploy a RoutingISM selecting the ism verifying a message by its origin domain",
+		Long: `Deploy a RoutingISM with the routes read from a JSON object mapping origin domains to the ids of
+existing isms, e.g. {"1234": "0x726f757465725f69736d000000000000000000000000000000000000000000"}.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			routes, err := readRoutes(args[1])
+			if err != nil {
+				return err
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			ismID, err := DeployRoutingIsm(ctx, broadcaster, routes)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("successfully deployed RoutingISM: %s\n", ismID)
+			for _, route := range routes {
+				fmt.Printf("domain %d: ism %s\n", route.Domain, route.Ism)
+			}
+
+			return nil
+		},
+	}
+
+	return deployCmd
+}
+
+func getDeployIgpCmd() *cobra.Command {
+	deployCmd := &cobra.Command{
+		Use:   "deploy-igp [celestia-grpc] [beneficiary] [gas-config...]",
+		Short: "Deploy an InterchainGasPaymaster owned by the beneficiary with <remote-domain>:<gas-overhead>:<token-exchange-rate>:<gas-price> gas configs",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			if _, err := sdk.AccAddressFromBech32(args[1]); err != nil {
+				return fmt.Errorf("failed to parse beneficiary: %w", err)
+			}
+
+			gasConfigs, err := parseGasConfigs(args[2:])
+			if err != nil {
+				return err
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			igpID, err := DeployIgp(ctx, broadcaster, args[1], gasConfigs)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("successfully deployed InterchainGasPaymaster: %s\n", igpID)
+			return nil
+		},
+	}
+	return deployCmd
+}
+
+func getClaimIgpCmd() *cobra.Command {
+	claimCmd := &cobra.Command{
+		Use:   "claim-igp [celestia-grpc] [igp-id]",
+		Short: "Claim the fees collected by an InterchainGasPaymaster to the signer, which must be its beneficiary",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			igpID, err := util.DecodeHexAddress(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to parse igp id: %w", err)
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			return ClaimIgp(ctx, broadcaster, igpID)
+		},
+	}
+	return claimCmd
+}
+
+func getDeploySyntheticCmd() *cobra.Command {
+	var (
+		ismIDFlag     string
+		mailboxDomain uint32
+	)
+
+	deployCmd := &cobra.Command{
+		Use:   "deploy-synthetic [celestia-grpc] [mailbox-id]",
+		Short: "Deploy a synthetic warp token for assets originating on remote chains, creating a mailbox on --local-domain if no mailbox id is provided",
+		Long: `Deploy a synthetic warp token for assets originating on remote chains, creating a mailbox on --local-domain
+if no mailbox id is provided. No denom is taken, as synthetic tokens have no origin denom and mint the derived
+denom hyperlane/<token-id>. The token is recorded as synthetic_token_id in the existing deployment config, which
+must have been written for the same mailbox, or in a new config if there is none.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			if len(args) == 1 && mailboxDomain == 0 {
+				return fmt.Errorf("either a mailbox id or --local-domain is required")
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			// the existing deployment config is only extended if it was written for the token mailbox
+			var existing *HyperlaneConfig
+			if _, err := os.Stat(configOutputPath()); err == nil {
+				if existing, err = readConfig(configOutputPath()); err != nil {
+					return err
+				}
+			}
+
+			var ismID, mailboxID util.HexAddress
+			if ismIDFlag != "" {
+				if ismID, err = util.DecodeHexAddress(ismIDFlag); err != nil {
+					return fmt.Errorf("failed to parse ism id: %w", err)
+				}
+			}
+
+			if len(args) == 2 {
+				if mailboxID, err = util.DecodeHexAddress(args[1]); err != nil {
+					return fmt.Errorf("failed to parse mailbox id: %w", err)
+				}
+
+				if existing != nil && !existing.MailboxID.Equal(mailboxID) {
+					return fmt.Errorf("%s was written for mailbox %s, not %s: use --config-out to record the token in a new config", configOutputPath(), existing.MailboxID, mailboxID)
+				}
+
+				if ismIDFlag == "" {
+					mailboxResp, err := coretypes.NewQueryClient(grpcConn).Mailbox(ctx, &coretypes.QueryMailboxRequest{Id: mailboxID.String()})
+					if err != nil {
+						return fmt.Errorf("failed to query mailbox: %w", err)
+					}
+					ismID = mailboxResp.Mailbox.DefaultIsm
+				}
+			} else {
+				if existing != nil {
+					return fmt.Errorf("%s was written for mailbox %s, not the mailbox created on --local-domain: use --config-out to record the token in a new config", configOutputPath(), existing.MailboxID)
+				}
+
+				if ismIDFlag == "" {
+					if ismID, err = setupNoopIsm(ctx, broadcaster); err != nil {
+						return err
+					}
+				}
+
+				hooksID, err := setupNoopHook(ctx, broadcaster)
+				if err != nil {
+					return err
+				}
+
+				if mailboxID, err = setupMailbox(ctx, broadcaster, ismID, hooksID, mailboxDomain); err != nil {
+					return err
+				}
+			}
+
+			tokenID, err := DeploySyntheticToken(ctx, broadcaster, mailboxID, ismID)
+			if err != nil {
+				return err
+			}
+
+			slog.Info("created synthetic token denom", "denom", "hyperlane/"+tokenID.String())
+
+			// record the token in the existing deployment config, if any
+			cfg := &HyperlaneConfig{IsmID: ismID, MailboxID: mailboxID}
+			if existing != nil {
+				cfg = existing
+			}
+			cfg.SyntheticTokenID = &tokenID
+
+			return writeConfig(cfg)
+		},
+	}
+
+	deployCmd.Flags().StringVar(&ismIDFlag, "ism-id", "", "ism set on the token (defaults to the default ism of the mailbox, or a new NoopISM for a new mailbox)")
+	deployCmd.Flags().Uint32Var(&mailboxDomain, "local-domain", 0, "local domain of the mailbox created when no mailbox id is provided")
+	addReuseExistingFlag(deployCmd)
+	return deployCmd
+}
+
+func getTransferCmd() *cobra.Command {
+	var (
+		gasLimit uint64
+		maxFee   string
+	)
+
+	transferCmd := &cobra.Command{
+		Use:   "transfer [celestia-grpc] [token-id] [dest-domain] [recipient] [amount]",
+		Short: "Send warp tokens to a recipient on a remote domain and print the dispatched message id",
+		Args:  cobra.ExactArgs(5),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			tokenID, err := util.DecodeHexAddress(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to parse token id: %w", err)
+			}
+
+			domain, err := strconv.ParseUint(args[2], 10, 32)
+			if err != nil {
+				return fmt.Errorf("failed to parse destination domain: %w", err)
+			}
+
+			amount, ok := math.NewIntFromString(args[4])
+			if !ok || !amount.IsPositive() {
+				return fmt.Errorf("invalid amount %q, expected a positive integer", args[4])
+			}
+
+			fee := sdk.NewCoin(denom, math.ZeroInt())
+			if maxFee != "" {
+				if fee, err = sdk.ParseCoinNormalized(maxFee); err != nil {
+					return fmt.Errorf("invalid --max-fee %q: %w", maxFee, err)
+				}
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			return RemoteTransfer(ctx, broadcaster, tokenID, uint32(domain), args[3], amount, math.NewIntFromUint64(gasLimit), fee)
+		},
+	}
+
+	transferCmd.Flags().Uint64Var(&gasLimit, "gas-limit", 0, "gas limit for handling the message on the destination (0 uses the gas of the enrolled remote router)")
+	transferCmd.Flags().StringVar(&maxFee, "max-fee", "", "maximum fee paid to the mailbox hooks, e.g. 1000utia (defaults to 0utia)")
+	return transferCmd
+}
+
+func getSignTxCmd() *cobra.Command {
+	signCmd := &cobra.Command{
+		Use:   "sign-tx [file]",
+		Short: "Sign an unsigned tx written by --generate-only, without connecting to the chain",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			bz, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read unsigned tx: %w", err)
+			}
+
+			var unsignedTx broadcaster.UnsignedTx
+			if err := json.Unmarshal(bz, &unsignedTx); err != nil {
+				return fmt.Errorf("failed to unmarshal unsigned tx: %w", err)
+			}
+
+			kr, keyName, _, err := newKeyring(enc)
+			if err != nil {
+				return err
+			}
+
+			signedTx, err := broadcaster.SignTx(ctx, enc, kr, keyName, signMode(), &unsignedTx)
+			if err != nil {
+				return err
+			}
+
+			txJSON, err := enc.TxConfig.TxJSONEncoder()(signedTx)
+			if err != nil {
+				return fmt.Errorf("failed to encode signed tx: %w", err)
+			}
+
+			return writeDocument(txJSON)
+		},
+	}
+	return signCmd
+}
+
+func getBroadcastTxCmd() *cobra.Command {
+	broadcastCmd := &cobra.Command{
+		Use:   "broadcast-tx [celestia-grpc] [file]",
+		Short: "Broadcast a tx signed by sign-tx and wait for its confirmation",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			bz, err := os.ReadFile(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to read signed tx: %w", err)
+			}
+
+			signedTx, err := enc.TxConfig.TxJSONDecoder()(bz)
+			if err != nil {
+				return fmt.Errorf("failed to decode signed tx: %w", err)
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			// signed txs are always submitted in sync mode, other broadcast modes only skip the confirmation
+			strategy := confirmStrategy
+			if broadcastMode != broadcastModeBlock {
+				strategy = confirmAsync
+			}
+
+			txService := txtypes.NewServiceClient(grpcConn)
+			confirmer, err := newConfirmer(strategy, txService)
+			if err != nil {
+				return err
+			}
+
+			res, err := broadcaster.BroadcastSignedTx(ctx, enc, txService, confirmer, signedTx)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("successfully broadcast tx %s at height %d\n", res.TxHash, res.Height)
+			return nil
+		},
+	}
+	return broadcastCmd
+}
+
+func getTransferMailboxOwnershipCmd() *cobra.Command {
+	transferCmd := &cobra.Command{
+		Use:   "transfer-mailbox-ownership [celestia-grpc] [mailbox-id] [new-owner]",
+		Short: "Transfer ownership of a mailbox owned by the signer, e.g. to a multisig or governance account",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			mailboxID, err := util.DecodeHexAddress(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to parse mailbox id: %w", err)
+			}
+
+			if _, err := sdk.AccAddressFromBech32(args[2]); err != nil {
+				return fmt.Errorf("failed to parse new owner: %w", err)
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			return TransferMailboxOwnership(ctx, broadcaster, mailboxID, args[2])
+		},
+	}
+	return transferCmd
+}
+
+func getTransferTokenOwnershipCmd() *cobra.Command {
+	transferCmd := &cobra.Command{
+		Use:   "transfer-token-ownership [celestia-grpc] [token-id] [new-owner]",
+		Short: "Transfer ownership of a warp token owned by the signer, preserving its ism",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+			tokenID, err := util.DecodeHexAddress(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to parse token id: %w", err)
+			}
+
+			if _, err := sdk.AccAddressFromBech32(args[2]); err != nil {
+				return fmt.Errorf("failed to parse new owner: %w", err)
+			}
+
+			grpcAddr := args[0]
+			grpcConn, err := dialGRPC(grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to connect to gRPC: %w", err)
+			}
+			defer grpcConn.Close()
+
+			broadcaster, err := NewBroadcaster(enc, grpcConn)
+			if err != nil {
+				return err
+			}
+
+			return TransferTokenOwnership(ctx, broadcaster, tokenID, args[2])
+		},
+	}
+	return transferCmd
+}
+
+func getDaemonCmd() *cobra.Command {
+	daemonCmd := &cobra.Command{
+		Use:   "daemon [celestia-grpc]",
+		Short: "Hold a gRPC connection to a celestia node and share it with subcommands run with --socket",
+		Long: `Hold a gRPC connection to a celestia node and forward the gRPC calls of subcommands run with the same
+--socket over it, so a scripted sequence of commands connects and performs the tls handshake only once. The
+daemon only reuses the connection: each subcommand still loads its keyring, queries the signer account and
+tracks its own sequence, so subcommands signing with the same key must not run concurrently. Their
+celestia-grpc argument is ignored. The daemon runs until interrupted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if grpcSocket == "" {
+				return fmt.Errorf("--socket is required to run the daemon")
+			}
+
+			return RunDaemon(cmd.Context(), args[0], grpcSocket)
+		},
+	}
+	return daemonCmd
 }
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/celestiaorg/hyp-deploy/pkg/broadcaster"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// confirmPoll polls the tx service by hash until the tx is included.
+	confirmPoll = "poll"
+	// confirmEvent subscribes to the tx event over the CometBFT websocket.
+	confirmEvent = "event"
+	// confirmAsync returns immediately after the tx passes CheckTx.
+	confirmAsync = "async"
+
+	// annotationRequiresConfirmation marks commands reading tx events or state written by their own txs.
+	annotationRequiresConfirmation = "hyp/requires-confirmation"
+)
+
+// newConfirmer returns the broadcaster.Confirmer for the provided --confirm strategy.
+func newConfirmer(strategy string, txService txtypes.ServiceClient) (broadcaster.Confirmer, error) {
+	opts := broadcaster.ConfirmOptions{
+		Timeout:     confirmTimeout,
+		Interval:    confirmInterval,
+		MaxInterval: confirmMaxInterval,
+	}
+
+	switch strategy {
+	case confirmPoll:
+		return broadcaster.NewPollConfirmer(txService, opts), nil
+	case confirmEvent:
+		return broadcaster.NewEventConfirmer(cometRPC, txService, opts), nil
+	case confirmAsync:
+		return broadcaster.NewAsyncConfirmer(), nil
+	default:
+		return nil, fmt.Errorf("unknown confirmation strategy %q, expected %q, %q or %q", strategy, confirmPoll, confirmEvent, confirmAsync)
+	}
+}
+
+// requireConfirmation marks the provided command as reading tx events, which are only available once a tx is
+// included, or state written by its own txs. The command is rejected up front with a --broadcast-mode or --confirm
+// strategy that does not wait for inclusion, rather than failing after its first txs were broadcast.
+func requireConfirmation(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+
+	cmd.Annotations[annotationRequiresConfirmation] = "true"
+	return cmd
+}
+
+// checkConfirmation returns an error if --confirm is set along with a --broadcast-mode that does not use a
+// confirmer, or if the provided command requires confirmed txs and the flags do not wait for inclusion.
+func checkConfirmation(cmd *cobra.Command) error {
+	if broadcastMode != broadcastModeBlock && cmd.Flags().Changed("confirm") {
+		return fmt.Errorf("--confirm only applies to --broadcast-mode %s, got --broadcast-mode %s", broadcastModeBlock, broadcastMode)
+	}
+
+	if _, ok := cmd.Annotations[annotationRequiresConfirmation]; !ok {
+		return nil
+	}
+
+	if broadcastMode != broadcastModeBlock {
+		return fmt.Errorf("%s reads the events of its txs and requires --broadcast-mode %s, got %q", cmd.CommandPath(), broadcastModeBlock, broadcastMode)
+	}
+
+	if confirmStrategy == confirmAsync {
+		return fmt.Errorf("%s reads the events of its txs and requires --confirm %s or %s, got %q", cmd.CommandPath(), confirmPoll, confirmEvent, confirmStrategy)
+	}
+
+	return nil
+}
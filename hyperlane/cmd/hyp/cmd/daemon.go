@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RunDaemon dials the celestia gRPC endpoint at grpcAddr once and forwards every gRPC call received on the unix
+// socket at socketPath over that connection until the provided context is cancelled. Calls are forwarded as
+// opaque bytes, so subcommands run with --socket can use any service exposed by the node. Only the connection is
+// shared, signing and sequence tracking remain in each subcommand.
+func RunDaemon(ctx context.Context, grpcAddr, socketPath string) error {
+	upstream, err := dialRemoteGRPC(grpcAddr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to gRPC: %w", err)
+	}
+	defer upstream.Close()
+
+	// connect eagerly so the connection is established before the first forwarded call
+	upstream.Connect()
+
+	lis, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s, remove it if it was left behind by a stopped daemon: %w", socketPath, err)
+	}
+
+	server := grpc.NewServer(
+		grpc.ForceServerCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(proxyHandler(upstream)),
+	)
+
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	slog.Info("forwarding gRPC calls", "socket", socketPath, "grpc_addr", grpcAddr)
+	return server.Serve(lis)
+}
+
+// rawFrame is a gRPC message forwarded by the daemon without decoding it.
+type rawFrame struct {
+	payload []byte
+}
+
+// rawCodec passes messages through as opaque bytes. It is named proto so forwarded calls keep their content-type.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	frame, ok := v.(*rawFrame)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+
+	return frame.payload, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	frame, ok := v.(*rawFrame)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+
+	frame.payload = append([]byte(nil), data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// proxyHandler returns a stream handler forwarding every call to the upstream connection, along with its metadata,
+// and relaying the upstream responses, header, trailer and status back to the caller.
+func proxyHandler(upstream *grpc.ClientConn) grpc.StreamHandler {
+	return func(_ any, serverStream grpc.ServerStream) error {
+		method, ok := grpc.MethodFromServerStream(serverStream)
+		if !ok {
+			return status.Error(codes.Internal, "failed to determine the method of the forwarded call")
+		}
+
+		ctx, cancel := context.WithCancel(serverStream.Context())
+		defer cancel()
+
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			md = md.Copy()
+			for key := range md {
+				// pseudo headers such as :authority are set by the upstream connection
+				if strings.HasPrefix(key, ":") {
+					delete(md, key)
+				}
+			}
+			ctx = metadata.NewOutgoingContext(ctx, md)
+		}
+
+		desc := &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}
+		clientStream, err := upstream.NewStream(ctx, desc, method, grpc.ForceCodec(rawCodec{}))
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			// a failed request aborts the upstream call, whose error is then returned by forwardResponses
+			if err := forwardRequests(serverStream, clientStream); err != nil {
+				cancel()
+			}
+		}()
+
+		return forwardResponses(clientStream, serverStream)
+	}
+}
+
+// forwardRequests sends the requests of the caller upstream and closes the upstream send side once the caller has.
+func forwardRequests(src grpc.ServerStream, dst grpc.ClientStream) error {
+	for {
+		frame := &rawFrame{}
+		if err := src.RecvMsg(frame); err != nil {
+			if errors.Is(err, io.EOF) {
+				return dst.CloseSend()
+			}
+			return err
+		}
+
+		if err := dst.SendMsg(frame); err != nil {
+			// io.EOF means the upstream call ended, its status is received by forwardResponses
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// forwardResponses sends the upstream header, responses and trailer back to the caller, returning the upstream
+// error so that its status is relayed as is.
+func forwardResponses(src grpc.ClientStream, dst grpc.ServerStream) error {
+	header, err := src.Header()
+	if err != nil {
+		return err
+	}
+
+	if err := dst.SendHeader(header); err != nil {
+		return err
+	}
+
+	for {
+		frame := &rawFrame{}
+		if err := src.RecvMsg(frame); err != nil {
+			dst.SetTrailer(src.Trailer())
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if err := dst.SendMsg(frame); err != nil {
+			return err
+		}
+	}
+}
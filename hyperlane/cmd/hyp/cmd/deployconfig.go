@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ismTypeNoop and ismTypeZK are the values accepted by --ism-type.
+	ismTypeNoop = "noop"
+	ismTypeZK   = "zk"
+)
+
+// DeployFileConfig is a deployment read from --config-file, in YAML or JSON. Flags and positional arguments passed
+// on the command line take precedence over its values.
+type DeployFileConfig struct {
+	GrpcAddr    string `json:"grpc_addr,omitempty"`
+	EvmRPC      string `json:"evm_rpc,omitempty"`
+	EvnodeRPC   string `json:"ev_node_rpc,omitempty"`
+	LocalDomain uint32 `json:"local_domain,omitempty"`
+
+	Ism      DeployIsmConfig `json:"ism"`
+	HookType string          `json:"hook_type,omitempty"`
+	Denom    string          `json:"denom,omitempty"`
+
+	Gas           string  `json:"gas,omitempty"`
+	GasAdjustment float64 `json:"gas_adjustment,omitempty"`
+	Fees          string  `json:"fees,omitempty"`
+	GasPrices     string  `json:"gas_prices,omitempty"`
+	FeeGranter    string  `json:"fee_granter,omitempty"`
+}
+
+// DeployIsmConfig selects the ism created by a deployment and, for zk isms, its circuit parameters.
+type DeployIsmConfig struct {
+	Type            string `json:"type,omitempty"`
+	Groth16Vkey     string `json:"groth16_vkey,omitempty"`
+	StateVkey       string `json:"state_vkey,omitempty"`
+	MessageVkey     string `json:"message_vkey,omitempty"`
+	Namespace       string `json:"namespace,omitempty"`
+	SequencerPubKey string `json:"sequencer_pubkey,omitempty"`
+}
+
+// deployOptions are the flags of the deploy and config print commands which are not persistent.
+type deployOptions struct {
+	localDomain uint32
+	ismType     string
+	batched     bool
+}
+
+// readDeployFileConfig reads the deployment config at path, rejecting unknown fields so that typos are not
+// silently ignored.
+func readDeployFileConfig(path string) (*DeployFileConfig, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg DeployFileConfig
+	if err := yaml.UnmarshalStrict(bz, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode config file %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
+
+// flagValues returns the values of the config file keyed by the flags they set, omitting unset values.
+func (c *DeployFileConfig) flagValues() map[string]string {
+	values := map[string]string{
+		"ism-type":         c.Ism.Type,
+		"groth16-vkey":     c.Ism.Groth16Vkey,
+		"state-vkey":       c.Ism.StateVkey,
+		"message-vkey":     c.Ism.MessageVkey,
+		"namespace":        c.Ism.Namespace,
+		"sequencer-pubkey": c.Ism.SequencerPubKey,
+		"hook-type":        c.HookType,
+		"collateral-denom": c.Denom,
+		"gas":              c.Gas,
+		"fees":             c.Fees,
+		"gas-prices":       c.GasPrices,
+		"fee-granter":      c.FeeGranter,
+	}
+
+	if c.LocalDomain != 0 {
+		values["local-domain"] = strconv.FormatUint(uint64(c.LocalDomain), 10)
+	}
+
+	if c.GasAdjustment != 0 {
+		values["gas-adjustment"] = strconv.FormatFloat(c.GasAdjustment, 'f', -1, 64)
+	}
+
+	for name, value := range values {
+		if value == "" {
+			delete(values, name)
+		}
+	}
+
+	return values
+}
+
+// applyDeployFileConfig sets the flags of cmd to the values of the config file, leaving flags passed on the
+// command line and flags cmd does not have untouched.
+func applyDeployFileConfig(cmd *cobra.Command, cfg *DeployFileConfig) error {
+	for name, value := range cfg.flagValues() {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("invalid %s %q in config file: %w", name, value, err)
+		}
+	}
+
+	return nil
+}
+
+// effectiveDeployConfig merges the config file with the positional arguments and flags of the command line, the
+// file values have already been applied to the flags by applyDeployFileConfig.
+func effectiveDeployConfig(args []string, opts deployOptions) *DeployFileConfig {
+	var cfg DeployFileConfig
+	if deployFileConfig != nil {
+		cfg = *deployFileConfig
+	}
+
+	for i, arg := range args {
+		switch i {
+		case 0:
+			cfg.GrpcAddr = arg
+		case 1:
+			cfg.EvmRPC = arg
+		case 2:
+			cfg.EvnodeRPC = arg
+		}
+	}
+
+	cfg.LocalDomain = opts.localDomain
+	cfg.Ism = DeployIsmConfig{Type: opts.ismType}
+	if opts.ismType == ismTypeZK {
+		cfg.Ism.Groth16Vkey = groth16VkeyPath
+		cfg.Ism.StateVkey = stateVkeyHex
+		cfg.Ism.MessageVkey = messageVkeyHex
+		cfg.Ism.Namespace = zkIsmNamespaceHex
+		cfg.Ism.SequencerPubKey = sequencerPubKeyHex
+	}
+
+	cfg.HookType = hookType
+	cfg.Denom = collateralDenom
+	cfg.Gas = gasSetting
+	cfg.GasAdjustment = gasAdjustment
+	cfg.Fees = fees
+	cfg.GasPrices = gasPrices
+	cfg.FeeGranter = feeGranter
+
+	return &cfg
+}
+
+// addDeployFlags registers the flags shared by the deploy and config print commands.
+func addDeployFlags(cmd *cobra.Command, opts *deployOptions) {
+	addZKIsmFlags(cmd)
+	addStackFlags(cmd)
+	cmd.Flags().Uint32Var(&opts.localDomain, "local-domain", defaultLocalDomain, "hyperlane domain of the deployed mailbox")
+	cmd.Flags().StringVar(&opts.ismType, "ism-type", ismTypeNoop, "ism created by the deployment (noop or zk)")
+	cmd.Flags().BoolVar(&opts.batched, "batch", false, "create the NoopISM and NoopHook in a single tx, only with --ism-type noop")
+	cmd.Flags().StringVar(&existingMailboxID, "mailbox-id", "", "existing mailbox to create the token against, skipping mailbox and hook creation")
+}
+
+func getDeployCmd() *cobra.Command {
+	var opts deployOptions
+
+	deployCmd := &cobra.Command{
+		Use:   "deploy [celestia-grpc] [evm-rpc] [ev-node-rpc]",
+		Short: "Deploy cosmosnative hyperlane components as described by --config-file and the command line",
+		Long: `Deploy cosmosnative hyperlane components with a NoopISM, as deploy-noopism, or a ZKExecutionISM, as
+deploy-zkism. The addresses and parameters are read from --config-file, positional arguments and flags override
+the values of the file. Use "hyp config print" to show the merged config.`,
+		Args: cobra.MaximumNArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := effectiveDeployConfig(args, opts)
+			if cfg.GrpcAddr == "" {
+				return fmt.Errorf("no celestia gRPC address, pass it as the first argument or set grpc_addr in --config-file")
+			}
+
+			switch cfg.Ism.Type {
+			case ismTypeNoop:
+				return runDeployNoopIsm(cmd.Context(), cfg.GrpcAddr, cfg.LocalDomain, opts.batched)
+			case ismTypeZK:
+				if cfg.EvmRPC == "" || cfg.EvnodeRPC == "" {
+					return fmt.Errorf("--ism-type zk requires the EVM and ev-node RPC addresses, pass them as arguments or set evm_rpc and ev_node_rpc in --config-file")
+				}
+
+				if opts.batched {
+					return fmt.Errorf("--batch can only be used with --ism-type %s", ismTypeNoop)
+				}
+
+				return runDeployZKIsm(cmd.Context(), cfg.GrpcAddr, cfg.EvmRPC, cfg.EvnodeRPC, cfg.LocalDomain)
+			default:
+				return fmt.Errorf("unknown ism type %q, expected %q or %q", cfg.Ism.Type, ismTypeNoop, ismTypeZK)
+			}
+		},
+	}
+
+	addDeployFlags(deployCmd, &opts)
+	return deployCmd
+}
+
+func getConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect deployment configs read from --config-file",
+	}
+
+	var opts deployOptions
+
+	printCmd := &cobra.Command{
+		Use:   "print [celestia-grpc] [evm-rpc] [ev-node-rpc]",
+		Short: "Print the deployment config resulting from --config-file merged with the command line",
+		Args:  cobra.MaximumNArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := effectiveDeployConfig(args, opts)
+
+			if output == outputJSON {
+				out, err := json.MarshalIndent(cfg, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal config: %w", err)
+				}
+
+				fmt.Println(string(out))
+				return nil
+			}
+
+			out, err := yaml.Marshal(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to marshal config: %w", err)
+			}
+
+			fmt.Print(string(out))
+			return nil
+		},
+	}
+
+	addDeployFlags(printCmd, &opts)
+	configCmd.AddCommand(printCmd)
+	return configCmd
+}
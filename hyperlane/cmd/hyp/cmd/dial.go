@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// dialGRPC creates a gRPC client connection to the provided address. With --socket the connection is made to the
+// hyp daemon listening on the socket instead, which forwards calls over its own connection to the node.
+func dialGRPC(addr string) (*grpc.ClientConn, error) {
+	if grpcSocket != "" {
+		// the daemon holds the tls connection to the node, the local socket is plaintext
+		return grpc.NewClient("unix://"+grpcSocket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	return dialRemoteGRPC(addr)
+}
+
+// dialRemoteGRPC creates a gRPC client connection to the provided address. Connections use TLS unless
+// --grpc-insecure is set, optionally verifying the server against --grpc-tls-ca and presenting a client
+// certificate for mTLS.
+func dialRemoteGRPC(addr string) (*grpc.ClientConn, error) {
+	if grpcInsecure {
+		return grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	tlsConfig, err := grpcTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return grpc.NewClient(addr, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+}
+
+// grpcTLSConfig builds the client tls config from the --grpc-tls-* flags.
+func grpcTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if grpcTLSCA != "" {
+		caPEM, err := os.ReadFile(grpcTLSCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls ca: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in tls ca %s", grpcTLSCA)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if (grpcTLSCert == "") != (grpcTLSKey == "") {
+		return nil, fmt.Errorf("--grpc-tls-cert and --grpc-tls-key must be provided together")
+	}
+
+	if grpcTLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(grpcTLSCert, grpcTLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
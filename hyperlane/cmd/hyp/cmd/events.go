@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"encoding/hex"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
 
 	"github.com/bcp-innovations/hyperlane-cosmos/util"
 	ismtypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/01_interchain_security/types"
@@ -15,116 +17,235 @@ import (
 	"github.com/cosmos/gogoproto/proto"
 )
 
-func parseIsmIDFromZkISMEvents(events []abci.Event) util.HexAddress {
-	var ismID util.HexAddress
+func parseIsmIDFromZkISMEvents(events []abci.Event) (util.HexAddress, error) {
 	for _, evt := range events {
 		if evt.GetType() == proto.MessageName(&zkismtypes.EventCreateZKExecutionISM{}) {
 			event, err := sdk.ParseTypedEvent(evt)
 			if err != nil {
-				log.Fatalf("failed to parse typed event: %v", err)
+				return util.HexAddress{}, fmt.Errorf("failed to parse typed event: %w", err)
 			}
 
 			if ismEvent, ok := event.(*zkismtypes.EventCreateZKExecutionISM); ok {
-				fmt.Printf("successfully created zk execution ISM: %s\n", ismEvent)
-				ismID = ismEvent.Id
+				slog.Info("created ZKExecutionISM", "ism_id", ismEvent.Id, "owner", ismEvent.Owner)
+				return ismEvent.Id, nil
 			}
 		}
 	}
 
-	return ismID
+	return util.HexAddress{}, errEventNotFound(&zkismtypes.EventCreateZKExecutionISM{})
 }
 
-func parseIsmIDFromNoopISMEvents(events []abci.Event) util.HexAddress {
-	var ismID util.HexAddress
+func parseIsmIDFromNoopISMEvents(events []abci.Event) (util.HexAddress, error) {
 	for _, evt := range events {
 		if evt.GetType() == proto.MessageName(&ismtypes.EventCreateNoopIsm{}) {
 			event, err := sdk.ParseTypedEvent(evt)
 			if err != nil {
-				log.Fatalf("failed to parse typed event: %v", err)
+				return util.HexAddress{}, fmt.Errorf("failed to parse typed event: %w", err)
 			}
 
 			if ismEvent, ok := event.(*ismtypes.EventCreateNoopIsm); ok {
-				log.Printf("successfully created Noop ISM: %s\n", ismEvent)
-				ismID = ismEvent.IsmId
+				slog.Info("created NoopISM", "ism_id", ismEvent.IsmId, "owner", ismEvent.Owner)
+				return ismEvent.IsmId, nil
 			}
 		}
 	}
 
-	return ismID
+	return util.HexAddress{}, errEventNotFound(&ismtypes.EventCreateNoopIsm{})
 }
 
-func parseHooksIDFromEvents(events []abci.Event) util.HexAddress {
-	var hookID util.HexAddress
+func parseIsmIDFromRoutingISMEvents(events []abci.Event) (util.HexAddress, error) {
+	for _, evt := range events {
+		if evt.GetType() == proto.MessageName(&ismtypes.EventCreateRoutingIsm{}) {
+			event, err := sdk.ParseTypedEvent(evt)
+			if err != nil {
+				return util.HexAddress{}, fmt.Errorf("failed to parse typed event: %w", err)
+			}
+
+			if ismEvent, ok := event.(*ismtypes.EventCreateRoutingIsm); ok {
+				slog.Info("created RoutingISM", "ism_id", ismEvent.IsmId, "owner", ismEvent.Owner)
+				return ismEvent.IsmId, nil
+			}
+		}
+	}
+
+	return util.HexAddress{}, errEventNotFound(&ismtypes.EventCreateRoutingIsm{})
+}
+
+func parseHooksIDFromEvents(events []abci.Event) (util.HexAddress, error) {
 	for _, evt := range events {
 		if evt.GetType() == proto.MessageName(&hooktypes.EventCreateNoopHook{}) {
 			event, err := sdk.ParseTypedEvent(evt)
 			if err != nil {
-				log.Fatalf("failed to parse typed event: %v", err)
+				return util.HexAddress{}, fmt.Errorf("failed to parse typed event: %w", err)
 			}
 
 			if hookEvent, ok := event.(*hooktypes.EventCreateNoopHook); ok {
-				log.Printf("successfully created NoopHook: %s\n", hookEvent)
-				hookID = hookEvent.NoopHookId
+				slog.Info("created NoopHook", "hook_id", hookEvent.NoopHookId, "owner", hookEvent.Owner)
+				return hookEvent.NoopHookId, nil
+			}
+		}
+	}
+
+	return util.HexAddress{}, errEventNotFound(&hooktypes.EventCreateNoopHook{})
+}
+
+func parseMerkleTreeHookIDFromEvents(events []abci.Event) (util.HexAddress, error) {
+	for _, evt := range events {
+		if evt.GetType() == proto.MessageName(&hooktypes.EventCreateMerkleTreeHook{}) {
+			event, err := sdk.ParseTypedEvent(evt)
+			if err != nil {
+				return util.HexAddress{}, fmt.Errorf("failed to parse typed event: %w", err)
+			}
+
+			if hookEvent, ok := event.(*hooktypes.EventCreateMerkleTreeHook); ok {
+				slog.Info("created MerkleTreeHook", "hook_id", hookEvent.MerkleTreeHookId, "mailbox_id", hookEvent.MailboxId, "owner", hookEvent.Owner)
+				return hookEvent.MerkleTreeHookId, nil
+			}
+		}
+	}
+
+	return util.HexAddress{}, errEventNotFound(&hooktypes.EventCreateMerkleTreeHook{})
+}
+
+func parseIgpIDFromEvents(events []abci.Event) (util.HexAddress, error) {
+	for _, evt := range events {
+		if evt.GetType() == proto.MessageName(&hooktypes.EventCreateIgp{}) {
+			event, err := sdk.ParseTypedEvent(evt)
+			if err != nil {
+				return util.HexAddress{}, fmt.Errorf("failed to parse typed event: %w", err)
+			}
+
+			if igpEvent, ok := event.(*hooktypes.EventCreateIgp); ok {
+				slog.Info("created InterchainGasPaymaster", "igp_id", igpEvent.IgpId, "owner", igpEvent.Owner, "denom", igpEvent.Denom)
+				return igpEvent.IgpId, nil
+			}
+		}
+	}
+
+	return util.HexAddress{}, errEventNotFound(&hooktypes.EventCreateIgp{})
+}
+
+func parseClaimedAmountFromEvents(events []abci.Event) (string, error) {
+	for _, evt := range events {
+		if evt.GetType() == proto.MessageName(&hooktypes.EventClaimIgp{}) {
+			event, err := sdk.ParseTypedEvent(evt)
+			if err != nil {
+				return "", fmt.Errorf("failed to parse typed event: %w", err)
+			}
+
+			if claimEvent, ok := event.(*hooktypes.EventClaimIgp); ok {
+				slog.Info("claimed InterchainGasPaymaster fees", "igp_id", claimEvent.IgpId, "owner", claimEvent.Owner, "amount", claimEvent.Amount)
+				return claimEvent.Amount, nil
 			}
 		}
 	}
 
-	return hookID
+	return "", errEventNotFound(&hooktypes.EventClaimIgp{})
 }
 
-func parseMailboxIDFromEvents(events []abci.Event) util.HexAddress {
-	var mailboxID util.HexAddress
+func parseMailboxIDFromEvents(events []abci.Event) (util.HexAddress, error) {
 	for _, evt := range events {
 		if evt.GetType() == proto.MessageName(&coretypes.EventCreateMailbox{}) {
 			event, err := sdk.ParseTypedEvent(evt)
 			if err != nil {
-				log.Fatalf("failed to parse typed event: %v", err)
+				return util.HexAddress{}, fmt.Errorf("failed to parse typed event: %w", err)
 			}
 
 			if mailboxEvent, ok := event.(*coretypes.EventCreateMailbox); ok {
-				log.Printf("successfully created Mailbox: %s\n", mailboxEvent)
-				mailboxID = mailboxEvent.MailboxId
+				slog.Info("created Mailbox", "mailbox_id", mailboxEvent.MailboxId, "owner", mailboxEvent.Owner, "local_domain", mailboxEvent.LocalDomain)
+				return mailboxEvent.MailboxId, nil
 			}
 		}
 	}
 
-	return mailboxID
+	return util.HexAddress{}, errEventNotFound(&coretypes.EventCreateMailbox{})
 }
 
-func parseCollateralTokenIDFromEvents(events []abci.Event) util.HexAddress {
-	var tokenID util.HexAddress
+func parseCollateralTokenIDFromEvents(events []abci.Event) (util.HexAddress, error) {
 	for _, evt := range events {
 		if evt.GetType() == proto.MessageName(&warptypes.EventCreateCollateralToken{}) {
 			event, err := sdk.ParseTypedEvent(evt)
 			if err != nil {
-				log.Fatalf("failed to parse typed event: %v", err)
+				return util.HexAddress{}, fmt.Errorf("failed to parse typed event: %w", err)
 			}
 
 			if tokenEvent, ok := event.(*warptypes.EventCreateCollateralToken); ok {
-				log.Printf("successfully created CollateralToken: %s\n", tokenEvent)
-				tokenID = tokenEvent.TokenId
+				slog.Info("created CollateralToken", "token_id", tokenEvent.TokenId, "origin_mailbox", tokenEvent.OriginMailbox, "origin_denom", tokenEvent.OriginDenom)
+				return tokenEvent.TokenId, nil
 			}
 		}
 	}
 
-	return tokenID
+	return util.HexAddress{}, errEventNotFound(&warptypes.EventCreateCollateralToken{})
 }
 
-func parseReceiverContractFromEvents(events []abci.Event) string {
-	var recvContract string
+func parseSyntheticTokenIDFromEvents(events []abci.Event) (util.HexAddress, error) {
+	for _, evt := range events {
+		if evt.GetType() == proto.MessageName(&warptypes.EventCreateSyntheticToken{}) {
+			event, err := sdk.ParseTypedEvent(evt)
+			if err != nil {
+				return util.HexAddress{}, fmt.Errorf("failed to parse typed event: %w", err)
+			}
+
+			if tokenEvent, ok := event.(*warptypes.EventCreateSyntheticToken); ok {
+				slog.Info("created SyntheticToken", "token_id", tokenEvent.TokenId, "origin_mailbox", tokenEvent.OriginMailbox, "origin_denom", tokenEvent.OriginDenom)
+				return tokenEvent.TokenId, nil
+			}
+		}
+	}
+
+	return util.HexAddress{}, errEventNotFound(&warptypes.EventCreateSyntheticToken{})
+}
+
+// parseDispatchIDFromEvents returns the id of the message dispatched by the mailbox, which is the full 32-byte
+// keccak256 hash of the encoded message the destination chain reports as processed.
+func parseDispatchIDFromEvents(events []abci.Event) (util.HexAddress, error) {
+	for _, evt := range events {
+		if evt.GetType() == proto.MessageName(&coretypes.EventDispatch{}) {
+			event, err := sdk.ParseTypedEvent(evt)
+			if err != nil {
+				return util.HexAddress{}, fmt.Errorf("failed to parse typed event: %w", err)
+			}
+
+			if dispatchEvent, ok := event.(*coretypes.EventDispatch); ok {
+				raw, err := hex.DecodeString(strings.TrimPrefix(dispatchEvent.Message, "0x"))
+				if err != nil {
+					return util.HexAddress{}, fmt.Errorf("failed to decode dispatched message: %w", err)
+				}
+
+				message, err := util.ParseHyperlaneMessage(raw)
+				if err != nil {
+					return util.HexAddress{}, fmt.Errorf("failed to parse dispatched message: %w", err)
+				}
+
+				slog.Info("dispatched message", "message_id", message.Id(), "nonce", message.Nonce, "origin", message.Origin, "origin_mailbox", dispatchEvent.OriginMailboxId, "destination", dispatchEvent.Destination, "sender", message.Sender, "recipient", dispatchEvent.Recipient)
+				return message.Id(), nil
+			}
+		}
+	}
+
+	return util.HexAddress{}, errEventNotFound(&coretypes.EventDispatch{})
+}
+
+func parseReceiverContractFromEvents(events []abci.Event) (string, error) {
 	for _, evt := range events {
 		if evt.GetType() == proto.MessageName(&warptypes.EventEnrollRemoteRouter{}) {
 			event, err := sdk.ParseTypedEvent(evt)
 			if err != nil {
-				log.Fatalf("failed to parse typed event: %v", err)
+				return "", fmt.Errorf("failed to parse typed event: %w", err)
 			}
 
 			if enrollEvent, ok := event.(*warptypes.EventEnrollRemoteRouter); ok {
-				log.Printf("successfully enrolled remote router: %s\n", enrollEvent)
-				recvContract = enrollEvent.ReceiverContract
+				slog.Info("enrolled remote router", "token_id", enrollEvent.TokenId, "remote_domain", enrollEvent.ReceiverDomain, "receiver_contract", enrollEvent.ReceiverContract)
+				return enrollEvent.ReceiverContract, nil
 			}
 		}
 	}
 
-	return recvContract
+	return "", errEventNotFound(&warptypes.EventEnrollRemoteRouter{})
+}
+
+// errEventNotFound returns the error for a tx whose events do not contain the expected typed event.
+func errEventNotFound(msg proto.Message) error {
+	return fmt.Errorf("%s event not found in tx events", proto.MessageName(msg))
 }
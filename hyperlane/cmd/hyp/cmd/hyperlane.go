@@ -1,181 +1,1997 @@
 package cmd
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"encoding/binary"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
+	"math/big"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
 	"cosmossdk.io/math"
 	"github.com/bcp-innovations/hyperlane-cosmos/util"
+	ismtypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/01_interchain_security/types"
 	hooktypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/02_post_dispatch/types"
 	coretypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/types"
 	warptypes "github.com/bcp-innovations/hyperlane-cosmos/x/warp/types"
+	"github.com/celestiaorg/celestia-app/v6/app/encoding"
 	zkismtypes "github.com/celestiaorg/celestia-app/v6/x/zkism/types"
+	"github.com/celestiaorg/hyp-deploy/pkg/broadcaster"
 	rpcclient "github.com/cometbft/cometbft/rpc/client/http"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	evclient "github.com/evstack/ev-node/pkg/rpc/client"
+	evpb "github.com/evstack/ev-node/types/pb/evnode/v1"
 )
 
 const (
 	// Currently we hardcode this value here as this is the canonical namespace used by the
 	// infrastructure in this repo.
 	namespaceHex = "00000000000000000000000000000000000000a8045f161bf468bf4d44"
+
+	// defaultLocalDomain is the hyperlane domain identifier of deployed mailboxes unless set by --local-domain.
+	defaultLocalDomain = 69420
+
+	// proofKindStateTransition and proofKindStateMembership select the program verifying key used for zk proofs.
+	proofKindStateTransition = "state-transition"
+	proofKindStateMembership = "state-membership"
+
+	// daIncludedHeightKey is the ev-node store metadata key holding the height up to which blocks are included on
+	// Celestia, DAIncludedHeightKey in block/manager.go of github.com/evstack/ev-node v1.0.0-beta.5.
+	daIncludedHeightKey = "d"
+
+	// defaultGroth16VkeyPath is the groth16 verifying key used when --groth16-vkey is unset.
+	defaultGroth16VkeyPath = "testdata/vkeys/groth16_vk.bin"
+
+	// configFileName is the file name the deployed HyperlaneConfig is written to within the output directory.
+	configFileName = "hyperlane-cosmosnative.json"
+
+	// hookTypeNoop, hookTypeMerkle and hookTypeIgp select the hook used as the default and required hook of deployed mailboxes.
+	hookTypeNoop   = "noop"
+	hookTypeMerkle = "merkle"
+	hookTypeIgp    = "igp"
 )
 
-// SetupZkIsm deploys a new zk ism using the provided evm client to fetch the latest block
-// for the initial trusted height and trusted root.
-func SetupZKIsm(ctx context.Context, broadcaster *Broadcaster, ethClient *ethclient.Client, evnodeClient *evclient.Client) util.HexAddress {
-	block, err := ethClient.BlockByNumber(ctx, nil) // nil == latest
+// ZKIsmParams are the circuit parameters of a zk ism. An empty SequencerPubKey is fetched from ev-node.
+type ZKIsmParams struct {
+	Groth16Vkey         []byte
+	StateTransitionVkey []byte
+	StateMembershipVkey []byte
+	Namespace           []byte
+	SequencerPubKey     []byte
+}
+
+// SetupZkIsm deploys a new zk ism with the provided circuit parameters. The initial trusted height is the latest
+// evm block ev-node reports as included on Celestia, the trusted root is taken from that block via the evm client
+// and the Celestia height and header hash are those of the Celestia block the evm block was included in.
+func SetupZKIsm(ctx context.Context, broadcaster *broadcaster.Broadcaster, ethClient *ethclient.Client, evnodeClient *evclient.Client, params ZKIsmParams) (util.HexAddress, error) {
+	trustedHeight, err := getDAIncludedHeight(ctx, evnodeClient)
+	if err != nil {
+		return util.HexAddress{}, fmt.Errorf("failed to get da included height from ev-node: %w", err)
+	}
+
+	celestiaHeight, err := getCelestiaInclusionHeight(ctx, evnodeClient, trustedHeight)
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	slog.Info("got DA included block from ev-node", "height", trustedHeight, "celestia_height", celestiaHeight)
+
+	block, err := ethClient.BlockByNumber(ctx, new(big.Int).SetUint64(trustedHeight))
+	if err != nil {
+		return util.HexAddress{}, fmt.Errorf("failed to get evm block %d: %w", trustedHeight, err)
+	}
+
+	slog.Info("got block from ev-reth", "height", block.NumberU64())
+
+	pubKey := params.SequencerPubKey
+	if len(pubKey) == 0 {
+		if pubKey, err = getSequencerPubKey(ctx, evnodeClient); err != nil {
+			return util.HexAddress{}, fmt.Errorf("failed to get sequencer pubkey: %w", err)
+		}
+
+		slog.Info("got sequencer pubkey from ev-node", "pubkey", hex.EncodeToString(pubKey))
+	}
+
+	root, err := GetCelestiaBlockHash(ctx, cometRPC, celestiaHeight)
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	msgCreateZkExecutionISM := zkismtypes.MsgCreateZKExecutionISM{
+		Creator:             broadcaster.Address().String(),
+		StateRoot:           block.Header().Root.Bytes(),
+		Height:              block.NumberU64(),
+		CelestiaHeaderHash:  root[:],
+		CelestiaHeight:      celestiaHeight,
+		Namespace:           params.Namespace,
+		SequencerPublicKey:  pubKey,
+		Groth16Vkey:         params.Groth16Vkey,
+		StateTransitionVkey: params.StateTransitionVkey,
+		StateMembershipVkey: params.StateMembershipVkey,
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, &msgCreateZkExecutionISM)
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	ismID, err := parseIsmIDFromZkISMEvents(res.Events)
+	if err != nil {
+		return util.HexAddress{}, fmt.Errorf("failed to create ZKExecutionISM: %w", err)
+	}
+
+	return ismID, nil
+}
+
+// SetupWithIsm deploys the cosmosnative Hyperlane components using the provided ism identifier and returns
+// the resulting config. The mailbox is created on the provided local domain and the collateral token for the
+// provided origin denom.
+func SetupWithIsm(ctx context.Context, broadcaster *broadcaster.Broadcaster, ismID util.HexAddress, domain uint32, originDenom string) (*HyperlaneConfig, error) {
+	var (
+		mailboxID, hooksID util.HexAddress
+		igpID              *util.HexAddress
+		err                error
+	)
+
+	if err := validateLocalDomain(domain); err != nil {
+		return nil, err
+	}
+
+	if existingMailboxID != "" {
+		return setupWithExistingMailbox(ctx, broadcaster, ismID, originDenom)
+	}
+
+	switch hookType {
+	case hookTypeNoop:
+		if hooksID, err = setupNoopHook(ctx, broadcaster); err == nil {
+			mailboxID, err = setupMailbox(ctx, broadcaster, ismID, hooksID, domain)
+		}
+	case hookTypeMerkle:
+		mailboxID, hooksID, err = setupMerkleHookMailbox(ctx, broadcaster, ismID, domain)
+	case hookTypeIgp:
+		if hooksID, err = setupIgpHook(ctx, broadcaster); err == nil {
+			igpID = &hooksID
+			mailboxID, err = setupMailbox(ctx, broadcaster, ismID, hooksID, domain)
+		}
+	default:
+		err = fmt.Errorf("unknown hook type %q, expected %q, %q or %q", hookType, hookTypeNoop, hookTypeMerkle, hookTypeIgp)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tokenID, err := setupCollateralToken(ctx, broadcaster, ismID, mailboxID, originDenom)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HyperlaneConfig{
+		IsmID:     ismID,
+		HooksID:   hooksID,
+		HookType:  hookType,
+		IgpID:     igpID,
+		MailboxID: mailboxID,
+		TokenID:   tokenID,
+	}, nil
+}
+
+// setupWithExistingMailbox creates a collateral token using the provided ism against the mailbox set by
+// --mailbox-id, without creating a mailbox or hooks.
+func setupWithExistingMailbox(ctx context.Context, broadcaster *broadcaster.Broadcaster, ismID util.HexAddress, originDenom string) (*HyperlaneConfig, error) {
+	mailbox, err := queryExistingMailbox(ctx, broadcaster)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("using existing Mailbox", "mailbox_id", mailbox.Id, "owner", mailbox.Owner, "local_domain", mailbox.LocalDomain)
+
+	tokenID, err := setupCollateralToken(ctx, broadcaster, ismID, mailbox.Id, originDenom)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &HyperlaneConfig{
+		IsmID:     ismID,
+		MailboxID: mailbox.Id,
+		TokenID:   tokenID,
+	}
+
+	if mailbox.DefaultHook != nil {
+		cfg.HooksID = *mailbox.DefaultHook
+	}
+
+	return cfg, nil
+}
+
+// queryExistingMailbox returns the mailbox set by --mailbox-id, or an error if it does not exist.
+func queryExistingMailbox(ctx context.Context, broadcaster *broadcaster.Broadcaster) (*coretypes.Mailbox, error) {
+	mailboxID, err := util.DecodeHexAddress(existingMailboxID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mailbox id: %w", err)
+	}
+
+	res, err := coretypes.NewQueryClient(broadcaster.Conn()).Mailbox(ctx, &coretypes.QueryMailboxRequest{Id: mailboxID.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mailbox %s: %w", mailboxID, err)
+	}
+
+	return &res.Mailbox, nil
+}
+
+// SetupNoopStackBatched deploys a NoopISM based stack like deploy-noopism in fewer transactions. The NoopISM and
+// NoopHook do not depend on each other and are created in a single tx. The remaining msgs cannot be combined, as
+// each references the id of a component created by the previous tx: the mailbox references the ism and hook, the
+// collateral token the mailbox and the ism is set on the token by id. Ids are allocated from on-chain sequences
+// shared by all deployers, so they cannot be reliably predicted to combine these msgs.
+func SetupNoopStackBatched(ctx context.Context, broadcaster *broadcaster.Broadcaster, domain uint32, originDenom string) (*HyperlaneConfig, error) {
+	if hookType != hookTypeNoop {
+		return nil, fmt.Errorf("batched deployments only support hook type %q, got %q", hookTypeNoop, hookType)
+	}
+
+	if err := validateLocalDomain(domain); err != nil {
+		return nil, err
+	}
+
+	ismID, hooksID, err := setupNoopIsmAndHook(ctx, broadcaster)
+	if err != nil {
+		return nil, err
+	}
+
+	mailboxID, err := setupMailbox(ctx, broadcaster, ismID, hooksID, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenID, err := setupCollateralToken(ctx, broadcaster, ismID, mailboxID, originDenom)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HyperlaneConfig{
+		IsmID:     ismID,
+		HooksID:   hooksID,
+		HookType:  hookType,
+		MailboxID: mailboxID,
+		TokenID:   tokenID,
+	}, nil
+}
+
+// setupNoopIsmAndHook deploys a NoopISM and a NoopHook in a single tx, reusing existing ones with --reuse-existing.
+func setupNoopIsmAndHook(ctx context.Context, broadcaster *broadcaster.Broadcaster) (util.HexAddress, util.HexAddress, error) {
+	owner := broadcaster.Address().String()
+
+	ismID, ismFound, err := findIf(reuseExisting, func() (util.HexAddress, bool, error) {
+		return findNoopIsm(ctx, broadcaster, owner)
+	})
+	if err != nil {
+		return util.HexAddress{}, util.HexAddress{}, fmt.Errorf("failed to query existing isms: %w", err)
+	}
+
+	hooksID, hookFound, err := findIf(reuseExisting, func() (util.HexAddress, bool, error) {
+		return findNoopHook(ctx, broadcaster, owner)
+	})
+	if err != nil {
+		return util.HexAddress{}, util.HexAddress{}, err
+	}
+
+	var msgs []sdk.Msg
+	if ismFound {
+		slog.Info("reusing existing NoopISM", "ism_id", ismID)
+	} else {
+		msgs = append(msgs, &ismtypes.MsgCreateNoopIsm{Creator: owner})
+	}
+
+	if hookFound {
+		slog.Info("reusing existing NoopHook", "hook_id", hooksID)
+	} else {
+		msgs = append(msgs, &hooktypes.MsgCreateNoopHook{Owner: owner})
+	}
+
+	if len(msgs) == 0 {
+		return ismID, hooksID, nil
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, msgs...)
+	if err != nil {
+		return util.HexAddress{}, util.HexAddress{}, err
+	}
+
+	if !ismFound {
+		if ismID, err = parseIsmIDFromNoopISMEvents(res.Events); err != nil {
+			return util.HexAddress{}, util.HexAddress{}, fmt.Errorf("failed to create NoopISM: %w", err)
+		}
+	}
+
+	if !hookFound {
+		if hooksID, err = parseHooksIDFromEvents(res.Events); err != nil {
+			return util.HexAddress{}, util.HexAddress{}, fmt.Errorf("failed to create NoopHook: %w", err)
+		}
+	}
+
+	return ismID, hooksID, nil
+}
+
+// setupCollateralToken deploys a collateral token for the provided mailbox and origin denom and sets its ism, or
+// reuses an existing one with --reuse-existing.
+func setupCollateralToken(ctx context.Context, broadcaster *broadcaster.Broadcaster, ismID, mailboxID util.HexAddress, originDenom string) (util.HexAddress, error) {
+	owner := broadcaster.Address().String()
+
+	token, found, err := findIf(reuseExisting, func() (*warptypes.WrappedHypToken, bool, error) {
+		return findCollateralToken(ctx, broadcaster, owner, mailboxID, originDenom)
+	})
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	var tokenID util.HexAddress
+	if found {
+		if tokenID, err = util.DecodeHexAddress(token.Id); err != nil {
+			return util.HexAddress{}, err
+		}
+		slog.Info("reusing existing CollateralToken", "token_id", tokenID)
+	} else {
+		msgCreateCollateralToken := warptypes.MsgCreateCollateralToken{
+			Owner:         owner,
+			OriginMailbox: mailboxID,
+			OriginDenom:   originDenom,
+		}
+
+		res, err := broadcaster.BroadcastTx(ctx, &msgCreateCollateralToken)
+		if err != nil {
+			return util.HexAddress{}, err
+		}
+
+		if tokenID, err = parseCollateralTokenIDFromEvents(res.Events); err != nil {
+			return util.HexAddress{}, fmt.Errorf("failed to create CollateralToken: %w", err)
+		}
+	}
+
+	if !found || token.IsmId == nil || !token.IsmId.Equal(ismID) {
+		// set ism id on new collateral token (for some reason this can't be done on creation)
+		msgSetToken := warptypes.MsgSetToken{
+			Owner:    owner,
+			TokenId:  tokenID,
+			IsmId:    &ismID,
+			NewOwner: owner,
+		}
+
+		if _, err := broadcaster.BroadcastTx(ctx, &msgSetToken); err != nil {
+			return util.HexAddress{}, err
+		}
+	}
+
+	return tokenID, nil
+}
+
+// setupNoopIsm deploys a NoopISM, or reuses an existing one with --reuse-existing.
+func setupNoopIsm(ctx context.Context, broadcaster *broadcaster.Broadcaster) (util.HexAddress, error) {
+	owner := broadcaster.Address().String()
+
+	ismID, found, err := findIf(reuseExisting, func() (util.HexAddress, bool, error) {
+		return findNoopIsm(ctx, broadcaster, owner)
+	})
+	if err != nil {
+		return util.HexAddress{}, fmt.Errorf("failed to query existing isms: %w", err)
+	}
+
+	if found {
+		slog.Info("reusing existing NoopISM", "ism_id", ismID)
+		return ismID, nil
+	}
+
+	msgCreateNoopISM := ismtypes.MsgCreateNoopIsm{
+		Creator: owner,
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, &msgCreateNoopISM)
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	ismID, err = parseIsmIDFromNoopISMEvents(res.Events)
+	if err != nil {
+		return util.HexAddress{}, fmt.Errorf("failed to create NoopISM: %w", err)
+	}
+
+	return ismID, nil
+}
+
+// setupNoopHook deploys a NoopHook, or reuses an existing one with --reuse-existing.
+func setupNoopHook(ctx context.Context, broadcaster *broadcaster.Broadcaster) (util.HexAddress, error) {
+	owner := broadcaster.Address().String()
+
+	hooksID, found, err := findIf(reuseExisting, func() (util.HexAddress, bool, error) {
+		return findNoopHook(ctx, broadcaster, owner)
+	})
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	if found {
+		slog.Info("reusing existing NoopHook", "hook_id", hooksID)
+		return hooksID, nil
+	}
+
+	msgCreateNoopHooks := hooktypes.MsgCreateNoopHook{
+		Owner: owner,
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, &msgCreateNoopHooks)
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	hooksID, err = parseHooksIDFromEvents(res.Events)
+	if err != nil {
+		return util.HexAddress{}, fmt.Errorf("failed to create NoopHook: %w", err)
+	}
+
+	return hooksID, nil
+}
+
+// setupIgpHook deploys an InterchainGasPaymaster with the gas configs and beneficiary set by the --igp-* flags,
+// or reuses an existing one with --reuse-existing.
+func setupIgpHook(ctx context.Context, broadcaster *broadcaster.Broadcaster) (util.HexAddress, error) {
+	beneficiary := igpBeneficiary
+	if beneficiary == "" {
+		beneficiary = broadcaster.Address().String()
+	}
+
+	igpID, found, err := findIf(reuseExisting, func() (util.HexAddress, bool, error) {
+		return findIgp(ctx, broadcaster, beneficiary, denom)
+	})
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	if found {
+		slog.Info("reusing existing InterchainGasPaymaster", "igp_id", igpID)
+		return igpID, nil
+	}
+
+	gasConfigs, err := parseGasConfigs(igpGasConfigs)
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	return DeployIgp(ctx, broadcaster, beneficiary, gasConfigs)
+}
+
+// setupMailbox deploys a mailbox on the provided domain using the provided hook as the default and required hook,
+// or reuses an existing equivalent mailbox with --reuse-existing.
+func setupMailbox(ctx context.Context, broadcaster *broadcaster.Broadcaster, ismID, hooksID util.HexAddress, domain uint32) (util.HexAddress, error) {
+	owner := broadcaster.Address().String()
+
+	mailboxID, found, err := findIf(reuseExisting, func() (util.HexAddress, bool, error) {
+		return findMailbox(ctx, broadcaster, owner, ismID, hooksID, domain)
+	})
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	if found {
+		slog.Info("reusing existing Mailbox", "mailbox_id", mailboxID)
+		return mailboxID, nil
+	}
+
+	if err := checkLocalDomain(ctx, broadcaster, domain); err != nil {
+		return util.HexAddress{}, err
+	}
+
+	msgCreateMailBox := coretypes.MsgCreateMailbox{
+		Owner:        owner,
+		DefaultIsm:   ismID,
+		LocalDomain:  domain,
+		DefaultHook:  &hooksID,
+		RequiredHook: &hooksID,
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, &msgCreateMailBox)
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	mailboxID, err = parseMailboxIDFromEvents(res.Events)
+	if err != nil {
+		return util.HexAddress{}, fmt.Errorf("failed to create Mailbox: %w", err)
+	}
+
+	return mailboxID, nil
+}
+
+// setupMerkleHookMailbox deploys a mailbox and a MerkleTreeHook for it, then sets the hook as the default and
+// required hook of the mailbox. A merkle tree hook is bound to a single mailbox, so the mailbox is created first.
+func setupMerkleHookMailbox(ctx context.Context, broadcaster *broadcaster.Broadcaster, ismID util.HexAddress, domain uint32) (util.HexAddress, util.HexAddress, error) {
+	owner := broadcaster.Address().String()
+
+	type mailboxHook struct{ mailboxID, hookID util.HexAddress }
+	existing, found, err := findIf(reuseExisting, func() (mailboxHook, bool, error) {
+		mailboxID, hookID, found, err := findMerkleHookMailbox(ctx, broadcaster, owner, ismID, domain)
+		return mailboxHook{mailboxID, hookID}, found, err
+	})
+	if err != nil {
+		return util.HexAddress{}, util.HexAddress{}, err
+	}
+
+	if found {
+		slog.Info("reusing existing Mailbox with MerkleTreeHook", "mailbox_id", existing.mailboxID, "hook_id", existing.hookID)
+		return existing.mailboxID, existing.hookID, nil
+	}
+
+	if err := checkLocalDomain(ctx, broadcaster, domain); err != nil {
+		return util.HexAddress{}, util.HexAddress{}, err
+	}
+
+	msgCreateMailBox := coretypes.MsgCreateMailbox{
+		Owner:       owner,
+		DefaultIsm:  ismID,
+		LocalDomain: domain,
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, &msgCreateMailBox)
+	if err != nil {
+		return util.HexAddress{}, util.HexAddress{}, err
+	}
+
+	mailboxID, err := parseMailboxIDFromEvents(res.Events)
+	if err != nil {
+		return util.HexAddress{}, util.HexAddress{}, fmt.Errorf("failed to create Mailbox: %w", err)
+	}
+
+	msgCreateMerkleTreeHook := hooktypes.MsgCreateMerkleTreeHook{
+		Owner:     owner,
+		MailboxId: mailboxID,
+	}
+
+	res, err = broadcaster.BroadcastTx(ctx, &msgCreateMerkleTreeHook)
+	if err != nil {
+		return util.HexAddress{}, util.HexAddress{}, err
+	}
+
+	hookID, err := parseMerkleTreeHookIDFromEvents(res.Events)
+	if err != nil {
+		return util.HexAddress{}, util.HexAddress{}, fmt.Errorf("failed to create MerkleTreeHook: %w", err)
+	}
+
+	msgSetMailbox := coretypes.MsgSetMailbox{
+		Owner:        owner,
+		MailboxId:    mailboxID,
+		DefaultHook:  &hookID,
+		RequiredHook: &hookID,
+	}
+
+	if _, err := broadcaster.BroadcastTx(ctx, &msgSetMailbox); err != nil {
+		return util.HexAddress{}, util.HexAddress{}, err
+	}
+
+	return mailboxID, hookID, nil
+}
+
+// DeploySyntheticToken deploys a synthetic warp token on the provided mailbox and sets its ism. Synthetic tokens
+// mint the on-chain derived denom hyperlane/<token-id> for assets originating on remote chains.
+func DeploySyntheticToken(ctx context.Context, broadcaster *broadcaster.Broadcaster, mailboxID, ismID util.HexAddress) (util.HexAddress, error) {
+	owner := broadcaster.Address().String()
+
+	msgCreateSyntheticToken := warptypes.MsgCreateSyntheticToken{
+		Owner:         owner,
+		OriginMailbox: mailboxID,
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, &msgCreateSyntheticToken)
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	tokenID, err := parseSyntheticTokenIDFromEvents(res.Events)
+	if err != nil {
+		return util.HexAddress{}, fmt.Errorf("failed to create SyntheticToken: %w", err)
+	}
+
+	// the ism can only be set after creation, as for collateral tokens
+	msgSetToken := warptypes.MsgSetToken{
+		Owner:    owner,
+		TokenId:  tokenID,
+		IsmId:    &ismID,
+		NewOwner: owner,
+	}
+
+	if _, err := broadcaster.BroadcastTx(ctx, &msgSetToken); err != nil {
+		return util.HexAddress{}, err
+	}
+
+	return tokenID, nil
+}
+
+// DeployIgp deploys an InterchainGasPaymaster charging fees in the default denom, sets the provided destination
+// gas configs and transfers ownership to the beneficiary, who can then claim the collected fees.
+func DeployIgp(ctx context.Context, broadcaster *broadcaster.Broadcaster, beneficiary string, gasConfigs []*hooktypes.DestinationGasConfig) (util.HexAddress, error) {
+	owner := broadcaster.Address().String()
+
+	msgCreateIgp := hooktypes.MsgCreateIgp{
+		Owner: owner,
+		Denom: denom,
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, &msgCreateIgp)
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	igpID, err := parseIgpIDFromEvents(res.Events)
+	if err != nil {
+		return util.HexAddress{}, fmt.Errorf("failed to create InterchainGasPaymaster: %w", err)
+	}
+
+	var msgs []sdk.Msg
+	for _, gasConfig := range gasConfigs {
+		msgs = append(msgs, &hooktypes.MsgSetDestinationGasConfig{
+			Owner:                owner,
+			IgpId:                igpID,
+			DestinationGasConfig: gasConfig,
+		})
+	}
+
+	// ownership is transferred last as only the owner can set gas configs
+	if beneficiary != owner {
+		msgs = append(msgs, &hooktypes.MsgSetIgpOwner{
+			Owner:    owner,
+			IgpId:    igpID,
+			NewOwner: beneficiary,
+		})
+	}
+
+	if len(msgs) > 0 {
+		if _, err := broadcaster.BroadcastTxBatches(ctx, batchSize, msgs...); err != nil {
+			return util.HexAddress{}, fmt.Errorf("failed to configure igp %s: %w", igpID, err)
+		}
+	}
+
+	return igpID, nil
+}
+
+// ClaimIgp claims the fees collected by the provided InterchainGasPaymaster to the signer, which must be its owner,
+// and prints the claimed amount along with the new balance of the signer.
+func ClaimIgp(ctx context.Context, broadcaster *broadcaster.Broadcaster, igpID util.HexAddress) error {
+	igpRes, err := hooktypes.NewQueryClient(broadcaster.Conn()).Igp(ctx, &hooktypes.QueryIgpRequest{Id: igpID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to query igp: %w", err)
+	}
+
+	beneficiary := broadcaster.Address().String()
+	if igpRes.Igp.Owner != beneficiary {
+		return fmt.Errorf("signer %s is not the owner of igp %s, owned by %s", beneficiary, igpID, igpRes.Igp.Owner)
+	}
+
+	msgClaim := hooktypes.MsgClaim{
+		Sender: beneficiary,
+		IgpId:  igpID,
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, &msgClaim)
+	if err != nil {
+		return err
+	}
+
+	amount, err := parseClaimedAmountFromEvents(res.Events)
+	if err != nil {
+		return fmt.Errorf("failed to claim InterchainGasPaymaster fees: %w", err)
+	}
+
+	balanceRes, err := banktypes.NewQueryClient(broadcaster.Conn()).Balance(ctx, &banktypes.QueryBalanceRequest{
+		Address: beneficiary,
+		Denom:   igpRes.Igp.Denom,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query beneficiary balance: %w", err)
+	}
+
+	fmt.Printf("claimed: %s\n", amount)
+	fmt.Printf("beneficiary %s balance: %s\n", beneficiary, balanceRes.Balance)
+	return nil
+}
+
+// parseGasConfigs parses destination gas configs of the form <remote-domain>:<gas-overhead>:<token-exchange-rate>:<gas-price>.
+func parseGasConfigs(args []string) ([]*hooktypes.DestinationGasConfig, error) {
+	gasConfigs := make([]*hooktypes.DestinationGasConfig, len(args))
+	for i, arg := range args {
+		parts := strings.Split(arg, ":")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("invalid gas config %q, expected <remote-domain>:<gas-overhead>:<token-exchange-rate>:<gas-price>", arg)
+		}
+
+		domain, err := strconv.ParseUint(parts[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote domain in gas config %q: %w", arg, err)
+		}
+
+		var values [3]math.Int
+		for j, part := range parts[1:] {
+			value, ok := math.NewIntFromString(part)
+			if !ok || value.IsNegative() {
+				return nil, fmt.Errorf("invalid amount %q in gas config %q", part, arg)
+			}
+			values[j] = value
+		}
+
+		gasConfigs[i] = &hooktypes.DestinationGasConfig{
+			RemoteDomain: uint32(domain),
+			GasOverhead:  values[0],
+			GasOracle: &hooktypes.GasOracle{
+				TokenExchangeRate: values[1],
+				GasPrice:          values[2],
+			},
+		}
+	}
+
+	return gasConfigs, nil
+}
+
+// validateLocalDomain rejects the zero local domain, which is not a valid hyperlane domain.
+func validateLocalDomain(domain uint32) error {
+	if domain == 0 {
+		return fmt.Errorf("invalid local domain 0, expected a non-zero hyperlane domain")
+	}
+
+	return nil
+}
+
+// checkLocalDomain returns an error if the provided local domain is already used by an existing mailbox,
+// as multiple mailboxes on the same domain break message routing. With --force the collision is only logged.
+func checkLocalDomain(ctx context.Context, broadcaster *broadcaster.Broadcaster, domain uint32) error {
+	client := coretypes.NewQueryClient(broadcaster.Conn())
+
+	var nextKey []byte
+	for {
+		res, err := client.Mailboxes(ctx, &coretypes.QueryMailboxesRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return fmt.Errorf("failed to query mailboxes: %w", err)
+		}
+
+		for _, mailbox := range res.Mailboxes {
+			if mailbox.LocalDomain != domain {
+				continue
+			}
+
+			if !force {
+				return fmt.Errorf("local domain %d is already used by mailbox %s, use --force to deploy anyway", domain, mailbox.Id)
+			}
+
+			slog.Warn("local domain is already used by another mailbox", "domain", domain, "mailbox_id", mailbox.Id)
+			return nil
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// resolveStack returns the mailbox and token recorded in the provided config. Without a config it falls back to the
+// first mailbox and token on chain, which is only correct when a single stack has been deployed.
+func resolveStack(ctx context.Context, hypQueryClient coretypes.QueryClient, warpQueryClient warptypes.QueryClient, cfg *HyperlaneConfig) (coretypes.Mailbox, warptypes.WrappedHypToken, error) {
+	if cfg != nil {
+		mailboxResp, err := hypQueryClient.Mailbox(ctx, &coretypes.QueryMailboxRequest{Id: cfg.MailboxID.String()})
+		if err != nil {
+			return coretypes.Mailbox{}, warptypes.WrappedHypToken{}, fmt.Errorf("failed to query mailbox: %w", err)
+		}
+
+		tokenResp, err := warpQueryClient.Token(ctx, &warptypes.QueryTokenRequest{Id: cfg.TokenID.String()})
+		if err != nil {
+			return coretypes.Mailbox{}, warptypes.WrappedHypToken{}, fmt.Errorf("failed to query token: %w", err)
+		}
+
+		return mailboxResp.Mailbox, *tokenResp.Token, nil
+	}
+
+	mailboxResp, err := hypQueryClient.Mailboxes(ctx, &coretypes.QueryMailboxesRequest{})
+	if err != nil {
+		return coretypes.Mailbox{}, warptypes.WrappedHypToken{}, err
+	}
+
+	if len(mailboxResp.Mailboxes) == 0 {
+		return coretypes.Mailbox{}, warptypes.WrappedHypToken{}, fmt.Errorf("no mailboxes found")
+	}
+
+	tokenResp, err := warpQueryClient.Tokens(ctx, &warptypes.QueryTokensRequest{})
+	if err != nil {
+		return coretypes.Mailbox{}, warptypes.WrappedHypToken{}, err
+	}
+
+	if len(tokenResp.Tokens) == 0 {
+		return coretypes.Mailbox{}, warptypes.WrappedHypToken{}, fmt.Errorf("no tokens found")
+	}
+
+	mailbox, token := mailboxResp.Mailboxes[0], tokenResp.Tokens[0]
+	slog.Warn("no --config provided, using the first mailbox and token", "mailbox_id", mailbox.Id, "token_id", token.Id)
+	return mailbox, token, nil
+}
+
+// OverwriteIsm sets the provided ism as the default ism of the mailbox and the ism of the token, returning the
+// resulting config.
+func OverwriteIsm(ctx context.Context, broadcaster *broadcaster.Broadcaster, ismID util.HexAddress, mailbox coretypes.Mailbox, token warptypes.WrappedHypToken) (*HyperlaneConfig, error) {
+	msgSetMailbox := coretypes.MsgSetMailbox{
+		Owner:             broadcaster.Address().String(),
+		MailboxId:         mailbox.Id,
+		DefaultIsm:        &ismID,
+		RenounceOwnership: false,
+	}
+
+	tokenID, err := util.DecodeHexAddress(token.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token id: %w", err)
+	}
+
+	// set ism id on new collateral token (for some reason this can't be done on creation)
+	msgSetToken := warptypes.MsgSetToken{
+		Owner:    broadcaster.Address().String(),
+		TokenId:  tokenID,
+		IsmId:    &ismID,
+		NewOwner: broadcaster.Address().String(),
+	}
+
+	if _, err := broadcaster.BroadcastTxBatches(ctx, batchSize, &msgSetMailbox, &msgSetToken); err != nil {
+		return nil, err
+	}
+
+	return &HyperlaneConfig{
+		IsmID:     ismID,
+		HooksID:   *mailbox.RequiredHook,
+		MailboxID: mailbox.Id,
+		TokenID:   tokenID,
+	}, nil
+}
+
+// TransferMailboxOwnership transfers ownership of the provided mailbox to newOwner and verifies the change by
+// re-querying the mailbox. The signer must be the current owner, which is checked before broadcasting.
+func TransferMailboxOwnership(ctx context.Context, broadcaster *broadcaster.Broadcaster, mailboxID util.HexAddress, newOwner string) error {
+	hypQueryClient := coretypes.NewQueryClient(broadcaster.Conn())
+
+	mailboxResp, err := hypQueryClient.Mailbox(ctx, &coretypes.QueryMailboxRequest{Id: mailboxID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to query mailbox: %w", err)
+	}
+
+	owner := broadcaster.Address().String()
+	if mailboxResp.Mailbox.Owner != owner {
+		return fmt.Errorf("signer %s is not the owner of mailbox %s, owned by %s", owner, mailboxID, mailboxResp.Mailbox.Owner)
+	}
+
+	msgSetMailbox := coretypes.MsgSetMailbox{
+		Owner:     owner,
+		MailboxId: mailboxID,
+		NewOwner:  newOwner,
+	}
+
+	if _, err := broadcaster.BroadcastTx(ctx, &msgSetMailbox); err != nil {
+		return err
+	}
+
+	mailboxResp, err = hypQueryClient.Mailbox(ctx, &coretypes.QueryMailboxRequest{Id: mailboxID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to query mailbox: %w", err)
+	}
+
+	if mailboxResp.Mailbox.Owner != newOwner {
+		return fmt.Errorf("mailbox %s is owned by %s after the transfer, expected %s", mailboxID, mailboxResp.Mailbox.Owner, newOwner)
+	}
+
+	fmt.Printf("successfully transferred ownership of mailbox %s to %s\n", mailboxID, newOwner)
+	return nil
+}
+
+// TransferTokenOwnership transfers ownership of the provided warp token to newOwner, preserving its ism, and
+// verifies the change by re-querying the token. The signer must be the current owner, which is checked before
+// broadcasting.
+func TransferTokenOwnership(ctx context.Context, broadcaster *broadcaster.Broadcaster, tokenID util.HexAddress, newOwner string) error {
+	warpQueryClient := warptypes.NewQueryClient(broadcaster.Conn())
+
+	tokenResp, err := warpQueryClient.Token(ctx, &warptypes.QueryTokenRequest{Id: tokenID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to query token: %w", err)
+	}
+
+	owner := broadcaster.Address().String()
+	if tokenResp.Token.Owner != owner {
+		return fmt.Errorf("signer %s is not the owner of token %s, owned by %s", owner, tokenID, tokenResp.Token.Owner)
+	}
+
+	// MsgSetToken overwrites the ism, so the current one is set again
+	msgSetToken := warptypes.MsgSetToken{
+		Owner:    owner,
+		TokenId:  tokenID,
+		IsmId:    tokenResp.Token.IsmId,
+		NewOwner: newOwner,
+	}
+
+	if _, err := broadcaster.BroadcastTx(ctx, &msgSetToken); err != nil {
+		return err
+	}
+
+	tokenResp, err = warpQueryClient.Token(ctx, &warptypes.QueryTokenRequest{Id: tokenID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to query token: %w", err)
+	}
+
+	if tokenResp.Token.Owner != newOwner {
+		return fmt.Errorf("token %s is owned by %s after the transfer, expected %s", tokenID, tokenResp.Token.Owner, newOwner)
+	}
+
+	fmt.Printf("successfully transferred ownership of token %s to %s\n", tokenID, newOwner)
+	return nil
+}
+
+// MigrateHooks sets the default and required hook of each provided mailbox to the provided hook identifier,
+// reporting the outcome per mailbox. Failures are reported and do not abort the migration of the remaining mailboxes,
+// an error is returned at the end if any mailbox failed to migrate.
+func MigrateHooks(ctx context.Context, broadcaster *broadcaster.Broadcaster, hookID util.HexAddress, mailboxIDs []util.HexAddress) error {
+	var failed int
+	for _, mailboxID := range mailboxIDs {
+		msgSetMailbox := coretypes.MsgSetMailbox{
+			Owner:        broadcaster.Address().String(),
+			MailboxId:    mailboxID,
+			DefaultHook:  &hookID,
+			RequiredHook: &hookID,
+		}
+
+		if _, err := broadcaster.BroadcastTx(ctx, &msgSetMailbox); err != nil {
+			fmt.Printf("mailbox %s: failed: %v\n", mailboxID, err)
+			failed++
+			continue
+		}
+
+		fmt.Printf("mailbox %s: migrated to hook %s\n", mailboxID, hookID)
+	}
+
+	fmt.Printf("migrated %d/%d mailboxes\n", len(mailboxIDs)-failed, len(mailboxIDs))
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d mailboxes failed to migrate", failed, len(mailboxIDs))
+	}
+
+	return nil
+}
+
+// AnnounceValidator announces the storage location of the provided validator on the provided mailbox.
+func AnnounceValidator(ctx context.Context, broadcaster *broadcaster.Broadcaster, validator, storageLocation, signature string, mailboxID util.HexAddress) error {
+	signature, err := normalizeAnnounceSignature(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	msgAnnounceValidator := ismtypes.MsgAnnounceValidator{
+		Validator:       validator,
+		StorageLocation: storageLocation,
+		Signature:       signature,
+		MailboxId:       mailboxID,
+		Creator:         broadcaster.Address().String(),
+	}
+
+	if _, err := broadcaster.BroadcastTx(ctx, &msgAnnounceValidator); err != nil {
+		return err
+	}
+
+	fmt.Printf("successfully announced validator %s with storage location %s\n", validator, storageLocation)
+	return nil
+}
+
+// Announcement is a validator storage location announcement read by announce-validators.
+type Announcement struct {
+	Validator       string `json:"validator"`
+	StorageLocation string `json:"storage_location"`
+	Signature       string `json:"signature"`
+	MailboxID       string `json:"mailbox_id"`
+}
+
+// AnnounceValidators announces the provided validator storage locations, either one per tx or, if batched,
+// batchSize announcements per tx. Up to concurrency txs are submitted in parallel, signed with the locally tracked
+// sequence of the broadcaster. Failures are collected rather than aborting so that every entry is attempted, and a
+// per-entry report is printed at the end.
+func AnnounceValidators(ctx context.Context, broadcaster *broadcaster.Broadcaster, announcements []Announcement, batched bool, concurrency int) error {
+	results := make([]error, len(announcements))
+
+	var (
+		msgs    []sdk.Msg
+		indices []int
+	)
+	for i, a := range announcements {
+		msg, err := newAnnounceValidatorMsg(broadcaster.Address().String(), a)
+		if err != nil {
+			results[i] = err
+			continue
+		}
+
+		msgs = append(msgs, msg)
+		indices = append(indices, i)
+	}
+
+	size := 1
+	if batched {
+		size = batchSize
+		if size <= 0 || size > len(msgs) {
+			size = len(msgs)
+		}
+	}
+
+	txHashes := make([]string, len(announcements))
+
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// each job is the start of a batch, batches cover distinct entries so results are written without locking
+			for start := range jobs {
+				end := min(start+size, len(msgs))
+
+				responses, err := broadcaster.BroadcastTxBatches(ctx, size, msgs[start:end]...)
+				for _, i := range indices[start:end] {
+					if err != nil {
+						results[i] = err
+						continue
+					}
+					txHashes[i] = responses[0].TxHash
+				}
+			}
+		}()
+	}
+
+	for start := 0; start < len(msgs); start += size {
+		jobs <- start
+	}
+	close(jobs)
+	wg.Wait()
+
+	var failed int
+	for i, a := range announcements {
+		if results[i] != nil {
+			failed++
+			fmt.Printf("FAIL: validator %s: %v\n", a.Validator, results[i])
+			continue
+		}
+
+		fmt.Printf("OK: validator %s announced %s in tx %s\n", a.Validator, a.StorageLocation, txHashes[i])
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d announcements failed", failed, len(announcements))
+	}
+
+	return nil
+}
+
+// newAnnounceValidatorMsg validates the provided announcement and returns the msg announcing it.
+func newAnnounceValidatorMsg(creator string, a Announcement) (*ismtypes.MsgAnnounceValidator, error) {
+	signature, err := normalizeAnnounceSignature(a.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	mailboxID, err := util.DecodeHexAddress(a.MailboxID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mailbox id: %w", err)
+	}
+
+	return &ismtypes.MsgAnnounceValidator{
+		Validator:       a.Validator,
+		StorageLocation: a.StorageLocation,
+		Signature:       signature,
+		MailboxId:       mailboxID,
+		Creator:         creator,
+	}, nil
+}
+
+// readAnnouncements reads announcements from a JSON array, or from a CSV file with a
+// validator,storage_location,signature,mailbox_id row per announcement and an optional header row.
+func readAnnouncements(path string) ([]Announcement, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open announcements file: %w", err)
+	}
+	defer f.Close()
+
+	if !strings.EqualFold(filepath.Ext(path), ".csv") {
+		var announcements []Announcement
+		if err := json.NewDecoder(f).Decode(&announcements); err != nil {
+			return nil, fmt.Errorf("failed to decode announcements: %w", err)
+		}
+
+		return announcements, nil
+	}
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 4
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read announcements: %w", err)
+	}
+
+	if len(records) > 0 && records[0][0] == "validator" {
+		records = records[1:]
+	}
+
+	announcements := make([]Announcement, len(records))
+	for i, record := range records {
+		announcements[i] = Announcement{
+			Validator:       record[0],
+			StorageLocation: record[1],
+			Signature:       record[2],
+			MailboxID:       record[3],
+		}
+	}
+
+	return announcements, nil
+}
+
+// DeployRoutingIsm deploys a RoutingISM verifying messages with the ism routed to by their origin domain. Every
+// routed ism is checked to exist before the routing ism is created.
+func DeployRoutingIsm(ctx context.Context, broadcaster *broadcaster.Broadcaster, routes []ismtypes.Route) (util.HexAddress, error) {
+	ismQueryClient := ismtypes.NewQueryClient(broadcaster.Conn())
+	for _, route := range routes {
+		if _, err := ismQueryClient.Ism(ctx, &ismtypes.QueryIsmRequest{Id: route.Ism.String()}); err != nil {
+			return util.HexAddress{}, fmt.Errorf("failed to query ism %s routed to by domain %d: %w", route.Ism, route.Domain, err)
+		}
+	}
+
+	msgCreateRoutingIsm := ismtypes.MsgCreateRoutingIsm{
+		Creator: broadcaster.Address().String(),
+		Routes:  routes,
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, &msgCreateRoutingIsm)
+	if err != nil {
+		return util.HexAddress{}, err
+	}
+
+	ismID, err := parseIsmIDFromRoutingISMEvents(res.Events)
+	if err != nil {
+		return util.HexAddress{}, fmt.Errorf("failed to create RoutingISM: %w", err)
+	}
+
+	return ismID, nil
+}
+
+// readRoutes reads a JSON object mapping origin domains to the ids of the isms messages from them are routed to,
+// sorted by domain.
+func readRoutes(path string) ([]ismtypes.Route, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routes file: %w", err)
+	}
+
+	var entries map[string]string
+	if err := json.Unmarshal(bz, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode routes: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no routes found in %s", path)
+	}
+
+	routes := make([]ismtypes.Route, 0, len(entries))
+	for domainStr, ism := range entries {
+		domain, err := strconv.ParseUint(domainStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid origin domain %q: %w", domainStr, err)
+		}
+
+		ismID, err := util.DecodeHexAddress(ism)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ism id %q for domain %d: %w", ism, domain, err)
+		}
+
+		routes = append(routes, ismtypes.Route{Ism: ismID, Domain: uint32(domain)})
+	}
+
+	slices.SortFunc(routes, func(a, b ismtypes.Route) int {
+		return cmp.Compare(a.Domain, b.Domain)
+	})
+
+	return routes, nil
+}
+
+// RouterEnrollment is a remote router enrolled by enroll-from-config.
+type RouterEnrollment struct {
+	RemoteDomain   uint32 `json:"remote_domain"`
+	RemoteContract string `json:"remote_contract"`
+	Gas            string `json:"gas,omitempty"`
+}
+
+// EnrollRoutersFromConfig enrolls the provided remote routers, keyed by the id or origin denom of the token they
+// are enrolled on, one per tx. Up to concurrency txs are submitted in parallel, signed with the locally tracked
+// sequence of the broadcaster. Failures are collected rather than aborting so that every enrollment is attempted.
+// Enrolled routers are confirmed by re-querying the remote routers of each token, and a per-enrollment report is
+// printed at the end.
+func EnrollRoutersFromConfig(ctx context.Context, broadcaster *broadcaster.Broadcaster, routers map[string][]RouterEnrollment, concurrency int) error {
+	warpQueryClient := warptypes.NewQueryClient(broadcaster.Conn())
+	owner := broadcaster.Address().String()
+
+	type enrollment struct {
+		tokenID util.HexAddress
+		router  RouterEnrollment
+		err     error
+	}
+
+	keys := slices.Sorted(maps.Keys(routers))
+
+	var enrollments []*enrollment
+	groups := make(map[string][]*enrollment, len(keys))
+	for _, key := range keys {
+		tokenID, err := resolveTokenID(ctx, warpQueryClient, owner, key)
+		for _, r := range routers[key] {
+			e := &enrollment{tokenID: tokenID, router: r, err: err}
+			enrollments = append(enrollments, e)
+			groups[key] = append(groups[key], e)
+		}
+	}
+
+	var wg sync.WaitGroup
+	jobs := make(chan *enrollment)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for e := range jobs {
+				e.err = enrollRemoteRouter(ctx, broadcaster, e.tokenID, e.router)
+			}
+		}()
+	}
+
+	for _, e := range enrollments {
+		// enrollments on tokens that failed to resolve are reported without broadcasting
+		if e.err == nil {
+			jobs <- e
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var failed int
+	for _, key := range keys {
+		var enrolled []*enrollment
+		for _, e := range groups[key] {
+			if e.err != nil {
+				failed++
+				fmt.Printf("FAIL: token %s: remote router %s on domain %d: %v\n", key, e.router.RemoteContract, e.router.RemoteDomain, e.err)
+				continue
+			}
+
+			enrolled = append(enrolled, e)
+		}
+
+		if len(enrolled) == 0 {
+			continue
+		}
+
+		current, err := queryRemoteRouters(ctx, warpQueryClient, enrolled[0].tokenID.String())
+		if err != nil {
+			return err
+		}
+
+		for _, e := range enrolled {
+			contract, _ := normalizeReceiverContract(e.router.RemoteContract)
+			if !slices.Contains(current, remoteRouterInfo{ReceiverDomain: e.router.RemoteDomain, ReceiverContract: contract}) {
+				failed++
+				fmt.Printf("FAIL: token %s: remote router %s on domain %d not found after enrollment\n", key, e.router.RemoteContract, e.router.RemoteDomain)
+				continue
+			}
+
+			fmt.Printf("OK: token %s: enrolled remote router %s on domain %d\n", key, contract, e.router.RemoteDomain)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d enrollments failed", failed, len(enrollments))
+	}
+
+	return nil
+}
+
+// enrollRemoteRouter enrolls the provided remote router on the token with the provided identifier.
+func enrollRemoteRouter(ctx context.Context, broadcaster *broadcaster.Broadcaster, tokenID util.HexAddress, r RouterEnrollment) error {
+	gas := math.ZeroInt()
+	if r.Gas != "" {
+		var ok bool
+		if gas, ok = math.NewIntFromString(r.Gas); !ok || gas.IsNegative() {
+			return fmt.Errorf("invalid gas %q, expected a non-negative integer", r.Gas)
+		}
+	}
+
+	msg, err := newEnrollRemoteRouterMsg(broadcaster.Address().String(), tokenID, r.RemoteDomain, r.RemoteContract, gas)
+	if err != nil {
+		return err
+	}
+
+	_, err = broadcaster.BroadcastTx(ctx, msg)
+	return err
+}
+
+// resolveTokenID returns the token identified by the provided key, either a token id or the origin denom of a
+// token owned by owner.
+func resolveTokenID(ctx context.Context, warpQueryClient warptypes.QueryClient, owner, key string) (util.HexAddress, error) {
+	if tokenID, err := util.DecodeHexAddress(key); err == nil {
+		return tokenID, nil
+	}
+
+	var (
+		matches []string
+		nextKey []byte
+	)
+	for {
+		res, err := warpQueryClient.Tokens(ctx, &warptypes.QueryTokensRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return util.HexAddress{}, fmt.Errorf("failed to query tokens: %w", err)
+		}
+
+		for _, token := range res.Tokens {
+			if token.Owner == owner && token.OriginDenom == key {
+				matches = append(matches, token.Id)
+			}
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = res.Pagination.NextKey
+	}
+
+	switch len(matches) {
+	case 0:
+		return util.HexAddress{}, fmt.Errorf("no token with origin denom %s owned by %s", key, owner)
+	case 1:
+		return util.DecodeHexAddress(matches[0])
+	default:
+		return util.HexAddress{}, fmt.Errorf("origin denom %s matches %d tokens owned by %s, use a token id: %s", key, len(matches), owner, strings.Join(matches, ", "))
+	}
+}
+
+// readRouterEnrollments reads a JSON object mapping token ids or origin denoms to the remote routers to enroll.
+func readRouterEnrollments(path string) (map[string][]RouterEnrollment, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routers file: %w", err)
+	}
+
+	var routers map[string][]RouterEnrollment
+	if err := json.Unmarshal(bz, &routers); err != nil {
+		return nil, fmt.Errorf("failed to decode routers: %w", err)
+	}
+
+	return routers, nil
+}
+
+// normalizeAnnounceSignature returns the provided hex encoded ECDSA signature as a 0x-prefixed 65-byte (r,s,v)
+// signature with a recovery id of 27 or 28, as expected by the ism module. It accepts 65-byte signatures with a
+// recovery id of 0/1, 27/28 or an EIP-155 encoded v, and 64-byte EIP-2098 compact (r,s) signatures.
+func normalizeAnnounceSignature(signature string) (string, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode hex signature: %w", err)
+	}
+
+	switch len(sig) {
+	case 64:
+		// EIP-2098: the y-parity is stored in the highest bit of s
+		v := sig[32] >> 7
+		sig[32] &= 0x7f
+		sig = append(sig, 27+v)
+	case 65:
+		switch v := sig[64]; {
+		case v == 0 || v == 1:
+			sig[64] = 27 + v
+		case v == 27 || v == 28:
+		case v >= 35:
+			// EIP-155: v = chainID * 2 + 35 + recovery id
+			sig[64] = 27 + (v-35)%2
+		default:
+			return "", fmt.Errorf("invalid recovery id %d", v)
+		}
+	default:
+		return "", fmt.Errorf("expected a 64-byte (r,s) or 65-byte (r,s,v) signature, got %d bytes", len(sig))
+	}
+
+	return "0x" + hex.EncodeToString(sig), nil
+}
+
+// SetupRemoteRouter links the provided token identifier on the cosmosnative deployment with the receiver contract on the counterparty.
+// For example: if the provided token identifier is a collateral token (e.g. utia), the receiverContract is expected to be the
+// contract address for the corresponding synthetic token on the counterparty. The provided gas is the destination gas
+// paid for handling messages on the counterparty.
+func SetupRemoteRouter(ctx context.Context, broadcaster *broadcaster.Broadcaster, tokenID util.HexAddress, domain uint32, receiverContract string, gas math.Int) error {
+	msgEnrollRemoteRouter, err := newEnrollRemoteRouterMsg(broadcaster.Address().String(), tokenID, domain, receiverContract, gas)
+	if err != nil {
+		return err
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, msgEnrollRemoteRouter)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	fmt.Printf("successfully got block %d from ev-reth\n", block.NumberU64())
+	recvContract, err := parseReceiverContractFromEvents(res.Events)
+	if err != nil {
+		return fmt.Errorf("failed to enroll remote router: %w", err)
+	}
+
+	fmt.Printf("successfully registered remote router on Hyperlane cosmosnative: \n%s", recvContract)
+	return nil
+}
 
-	namespace, err := hex.DecodeString(namespaceHex)
+// newEnrollRemoteRouterMsg validates the provided remote router and returns the msg enrolling it on the token with
+// the provided identifier. A warning is logged for zero gas.
+func newEnrollRemoteRouterMsg(owner string, tokenID util.HexAddress, domain uint32, receiverContract string, gas math.Int) (*warptypes.MsgEnrollRemoteRouter, error) {
+	receiverContract, err := normalizeReceiverContract(receiverContract)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("invalid remote contract: %w", err)
+	}
+
+	if gas.IsZero() {
+		slog.Warn("enrolling remote router with zero gas, the relayer cannot cover execution on EVM destinations", "remote_domain", domain)
 	}
 
-	pubKey, err := getSequencerPubKey(ctx, evnodeClient)
+	return &warptypes.MsgEnrollRemoteRouter{
+		Owner:   owner,
+		TokenId: tokenID,
+		RemoteRouter: &warptypes.RemoteRouter{
+			ReceiverDomain:   domain,
+			ReceiverContract: receiverContract,
+			Gas:              gas,
+		},
+	}, nil
+}
+
+// CheckMultisig queries the MerkleRootMultisigIsm with the provided identifier and cross-references its validator set
+// against the storage locations announced on the provided mailbox. It reports which validators have announced and whether
+// the number of announced validators meets the ISM threshold, returning an error if it does not.
+func CheckMultisig(ctx context.Context, enc encoding.Config, ismQueryClient ismtypes.QueryClient, ismID, mailboxID util.HexAddress) error {
+	ismResp, err := ismQueryClient.Ism(ctx, &ismtypes.QueryIsmRequest{Id: ismID.String()})
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("failed to query ism: %w", err)
+	}
+
+	if ismResp.Ism.TypeUrl != "/"+proto.MessageName(&ismtypes.MerkleRootMultisigISM{}) {
+		return fmt.Errorf("ism %s is not a MerkleRootMultisigIsm: %s", ismID, ismResp.Ism.TypeUrl)
 	}
 
-	fmt.Printf("successfully got pubkey from ev-node %x\n", pubKey)
+	var multisig ismtypes.MerkleRootMultisigISM
+	if err := enc.Codec.Unmarshal(ismResp.Ism.Value, &multisig); err != nil {
+		return fmt.Errorf("unmarshal ism: %w", err)
+	}
 
-	groth16Vkey := readGroth16Vkey()
-	stateTransitionVkey := readStateTransitionVkey()
-	stateMembershipVkey := readStateMembershipVkey()
+	var announced uint32
+	for _, validator := range multisig.Validators {
+		res, err := ismQueryClient.AnnouncedStorageLocations(ctx, &ismtypes.QueryAnnouncedStorageLocationsRequest{
+			MailboxId:        mailboxID.String(),
+			ValidatorAddress: validator,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to query announced storage locations of validator %s: %w", validator, err)
+		}
 
-	root, height := GetCelestiaBlockHashAndHeight(ctx, "http://celestia-validator:26657")
+		if len(res.StorageLocations) == 0 {
+			fmt.Printf("validator %s: not announced\n", validator)
+			continue
+		}
 
-	fmt.Printf("successfully got celestia root and height: %x, %d\n", root, height)
+		announced++
+		fmt.Printf("validator %s: announced %s\n", validator, strings.Join(res.StorageLocations, ", "))
+	}
 
-	msgCreateZkExecutionISM := zkismtypes.MsgCreateZKExecutionISM{
-		Creator:             broadcaster.address.String(),
-		StateRoot:           block.Header().Root.Bytes(),
-		Height:              block.NumberU64(),
-		CelestiaHeaderHash:  root[:],
-		CelestiaHeight:      height,
-		Namespace:           namespace,
-		SequencerPublicKey:  pubKey,
-		Groth16Vkey:         groth16Vkey,
-		StateTransitionVkey: stateTransitionVkey,
-		StateMembershipVkey: stateMembershipVkey,
+	if announced < multisig.Threshold {
+		return fmt.Errorf("%d/%d validators announced, threshold %d not met", announced, len(multisig.Validators), multisig.Threshold)
+	}
+
+	fmt.Printf("OK: %d/%d validators announced, threshold %d met\n", announced, len(multisig.Validators), multisig.Threshold)
+	return nil
+}
+
+// RemoteTransfer sends amount of the provided warp token to the recipient on the destination domain and prints the
+// id of the dispatched message, which can be used to track relaying.
+func RemoteTransfer(ctx context.Context, broadcaster *broadcaster.Broadcaster, tokenID util.HexAddress, domain uint32, recipient string, amount, gasLimit math.Int, maxFee sdk.Coin) error {
+	recipient, err := normalizeReceiverContract(recipient)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	recipientAddr, err := util.DecodeHexAddress(recipient)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	msgRemoteTransfer := warptypes.MsgRemoteTransfer{
+		Sender:            broadcaster.Address().String(),
+		TokenId:           tokenID,
+		DestinationDomain: domain,
+		Recipient:         recipientAddr,
+		Amount:            amount,
+		GasLimit:          gasLimit,
+		MaxFee:            maxFee,
 	}
 
-	res := broadcaster.BroadcastTx(ctx, &msgCreateZkExecutionISM)
+	res, err := broadcaster.BroadcastTx(ctx, &msgRemoteTransfer)
+	if err != nil {
+		return err
+	}
 
-	ismID := parseIsmIDFromZkISMEvents(res.Events)
+	messageID, err := parseDispatchIDFromEvents(res.Events)
+	if err != nil {
+		return fmt.Errorf("failed to dispatch transfer: %w", err)
+	}
 
-	return ismID
+	fmt.Printf("successfully dispatched transfer to domain %d, message id: %s\n", domain, messageID)
+	return nil
 }
 
-// SetupWithIsm deploys the cosmosnative Hyperlane components using the provided ism identifier.
-func SetupWithIsm(ctx context.Context, broadcaster *Broadcaster, ismID util.HexAddress) {
-	msgCreateNoopHooks := hooktypes.MsgCreateNoopHook{
-		Owner: broadcaster.address.String(),
+// normalizeReceiverContract returns the receiver contract in the 32-byte Hyperlane address format.
+// Bare 20-byte EVM addresses are left-padded with zeros, 32-byte addresses are returned as is.
+func normalizeReceiverContract(contract string) (string, error) {
+	if common.IsHexAddress(contract) {
+		return util.HexAddress(common.BytesToHash(common.HexToAddress(contract).Bytes())).String(), nil
+	}
+
+	addr, err := util.DecodeHexAddress(contract)
+	if err != nil {
+		return "", fmt.Errorf("expected a 20-byte EVM address or a 32-byte hyperlane address (0x-prefixed hex), got %q: %w", contract, err)
 	}
 
-	res := broadcaster.BroadcastTx(ctx, &msgCreateNoopHooks)
-	hooksID := parseHooksIDFromEvents(res.Events)
+	return addr.String(), nil
+}
 
-	msgCreateMailBox := coretypes.MsgCreateMailbox{
-		Owner:        broadcaster.address.String(),
-		DefaultIsm:   ismID,
-		LocalDomain:  69420,
-		DefaultHook:  &hooksID,
-		RequiredHook: &hooksID,
+// Teardown unenrolls all remote routers of the token in the provided config. The cosmosnative modules do not
+// support removing tokens, mailboxes, isms or hooks, these are reported as retained.
+func Teardown(ctx context.Context, broadcaster *broadcaster.Broadcaster, warpQueryClient warptypes.QueryClient, cfg *HyperlaneConfig) error {
+	routersResp, err := warpQueryClient.RemoteRouters(ctx, &warptypes.QueryRemoteRoutersRequest{Id: cfg.TokenID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to query remote routers: %w", err)
 	}
 
-	res = broadcaster.BroadcastTx(ctx, &msgCreateMailBox)
-	mailboxID := parseMailboxIDFromEvents(res.Events)
+	var msgs []sdk.Msg
+	for _, router := range routersResp.RemoteRouters {
+		msgs = append(msgs, &warptypes.MsgUnrollRemoteRouter{
+			Owner:          broadcaster.Address().String(),
+			TokenId:        cfg.TokenID,
+			ReceiverDomain: router.ReceiverDomain,
+		})
+	}
 
-	msgCreateCollateralToken := warptypes.MsgCreateCollateralToken{
-		Owner:         broadcaster.address.String(),
-		OriginMailbox: mailboxID,
-		OriginDenom:   denom,
+	if len(msgs) > 0 {
+		if _, err := broadcaster.BroadcastTxBatches(ctx, batchSize, msgs...); err != nil {
+			return err
+		}
 	}
 
-	res = broadcaster.BroadcastTx(ctx, &msgCreateCollateralToken)
-	tokenID := parseCollateralTokenIDFromEvents(res.Events)
+	for _, router := range routersResp.RemoteRouters {
+		fmt.Printf("removed: remote router %s on domain %d\n", router.ReceiverContract, router.ReceiverDomain)
+	}
 
-	// set ism id on new collateral token (for some reason this can't be done on creation)
-	msgSetToken := warptypes.MsgSetToken{
-		Owner:    broadcaster.address.String(),
-		TokenId:  tokenID,
-		IsmId:    &ismID,
-		NewOwner: broadcaster.address.String(),
+	if len(routersResp.RemoteRouters) == 0 {
+		fmt.Printf("no remote routers enrolled for token %s\n", cfg.TokenID)
 	}
 
-	broadcaster.BroadcastTx(ctx, &msgSetToken)
+	fmt.Printf("retained: token %s (tokens cannot be removed)\n", cfg.TokenID)
+	fmt.Printf("retained: mailbox %s (mailboxes cannot be removed)\n", cfg.MailboxID)
+	fmt.Printf("retained: ism %s (isms cannot be removed)\n", cfg.IsmID)
+	fmt.Printf("retained: hooks %s (hooks cannot be removed)\n", cfg.HooksID)
+	return nil
+}
 
-	cfg := &HyperlaneConfig{
-		IsmID:     ismID,
-		HooksID:   hooksID,
-		MailboxID: mailboxID,
-		TokenID:   tokenID,
+// deployTxCount returns the number of transactions of a deployment with the configured hook type, including the
+// ism. It is an upper bound used to estimate fees, reused components and batching reduce the actual count.
+func deployTxCount() int {
+	// the ism is created in one tx, the collateral token is created and its ism set in two
+	const ismTxs, tokenTxs = 1, 2
+
+	if existingMailboxID != "" {
+		return ismTxs + tokenTxs
 	}
 
-	writeConfig(cfg)
+	switch hookType {
+	case hookTypeMerkle:
+		// the mailbox, the hook and setting the hook on the mailbox
+		return ismTxs + 3 + tokenTxs
+	case hookTypeIgp:
+		// the igp, its gas configs and owner, and the mailbox
+		return ismTxs + 3 + tokenTxs
+	default:
+		// the hook and the mailbox
+		return ismTxs + 2 + tokenTxs
+	}
 }
 
-func OverwriteIsm(ctx context.Context, broadcaster *Broadcaster, ismID util.HexAddress, mailbox coretypes.Mailbox, token warptypes.WrappedHypToken) {
-	msgSetMailbox := coretypes.MsgSetMailbox{
-		Owner:             broadcaster.address.String(),
-		MailboxId:         mailbox.Id,
-		DefaultIsm:        &ismID,
-		RenounceOwnership: false,
+// checkSignerBalance logs the signer address and fails if its balance cannot cover the estimated fees of the
+// provided number of transactions, unless --skip-balance-check or --generate-only is set. Fees are not checked
+// when they are paid by a --fee-granter.
+func checkSignerBalance(ctx context.Context, broadcaster *broadcaster.Broadcaster, txs int) error {
+	signer := broadcaster.Address()
+	fees := broadcaster.EstimatedFees(txs)
+
+	slog.Info("using signer", "address", signer, "txs", txs, "estimated_fees", fees)
+	if skipBalanceCheck || generateOnly {
+		return nil
 	}
 
-	tokenID, err := util.DecodeHexAddress(token.Id)
+	if feeGranter != "" {
+		slog.Info("fees are paid by the fee granter", "fee_granter", feeGranter)
+		return nil
+	}
+
+	bankQueryClient := banktypes.NewQueryClient(broadcaster.Conn())
+	for _, fee := range fees {
+		res, err := bankQueryClient.Balance(ctx, &banktypes.QueryBalanceRequest{Address: signer.String(), Denom: fee.Denom})
+		if err != nil {
+			return fmt.Errorf("failed to query balance of signer %s: %w", signer, err)
+		}
+
+		slog.Info("signer balance", "address", signer, "balance", res.Balance)
+		if res.Balance.Amount.LT(fee.Amount) {
+			return fmt.Errorf("signer %s balance of %s cannot cover the estimated fees of %s for %d txs, fund the account or pass --skip-balance-check", signer, res.Balance, fee, txs)
+		}
+	}
+
+	return nil
+}
+
+// ValidateDenom ensures the provided denom exists on chain by checking it has a non-zero total supply.
+func ValidateDenom(ctx context.Context, bankQueryClient banktypes.QueryClient, denom string) error {
+	res, err := bankQueryClient.SupplyOf(ctx, &banktypes.QuerySupplyOfRequest{Denom: denom})
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("failed to query supply of %s: %w", denom, err)
 	}
 
-	// set ism id on new collateral token (for some reason this can't be done on creation)
-	msgSetToken := warptypes.MsgSetToken{
-		Owner:    broadcaster.address.String(),
-		TokenId:  tokenID,
-		IsmId:    &ismID,
-		NewOwner: broadcaster.address.String(),
+	if res.Amount.IsZero() {
+		return fmt.Errorf("denom %s does not exist on chain", denom)
+	}
+
+	return nil
+}
+
+// CheckIsmConsistency compares the ism of the provided token against the default ism of the provided mailbox
+// and reports whether they match. A token without an ism falls back to the mailbox default ism.
+func CheckIsmConsistency(ctx context.Context, hypQueryClient coretypes.QueryClient, warpQueryClient warptypes.QueryClient, mailboxID, tokenID util.HexAddress) (bool, error) {
+	mailboxResp, err := hypQueryClient.Mailbox(ctx, &coretypes.QueryMailboxRequest{Id: mailboxID.String()})
+	if err != nil {
+		return false, fmt.Errorf("failed to query mailbox: %w", err)
 	}
 
-	broadcaster.BroadcastTx(ctx, &msgSetMailbox, &msgSetToken)
+	tokenResp, err := warpQueryClient.Token(ctx, &warptypes.QueryTokenRequest{Id: tokenID.String()})
+	if err != nil {
+		return false, fmt.Errorf("failed to query token: %w", err)
+	}
 
-	cfg := &HyperlaneConfig{
-		IsmID:     ismID,
-		HooksID:   *mailbox.RequiredHook,
-		MailboxID: mailbox.Id,
-		TokenID:   tokenID,
+	defaultIsm := mailboxResp.Mailbox.DefaultIsm
+	fmt.Printf("mailbox default ism: %s\n", defaultIsm)
+
+	if tokenResp.Token.IsmId == nil {
+		fmt.Println("OK: token has no ism set and uses the mailbox default ism")
+		return true, nil
 	}
 
-	writeConfig(cfg)
+	fmt.Printf("token ism: %s\n", tokenResp.Token.IsmId)
+
+	if !tokenResp.Token.IsmId.Equal(defaultIsm) {
+		fmt.Println("WARNING: token ism differs from the mailbox default ism")
+		return false, nil
+	}
+
+	fmt.Println("OK: token ism matches the mailbox default ism")
+	return true, nil
 }
 
-// SetupRemoteRouter links the provided token identifier on the cosmosnative deployment with the receiver contract on the counterparty.
-// For example: if the provided token identifier is a collateral token (e.g. utia), the receiverContract is expected to be the
-// contract address for the corresponding synthetic token on the counterparty.
-func SetupRemoteRouter(ctx context.Context, broadcaster *Broadcaster, tokenID util.HexAddress, domain uint32, receiverContract string) {
-	msgEnrollRemoteRouter := warptypes.MsgEnrollRemoteRouter{
-		Owner:   broadcaster.address.String(),
-		TokenId: tokenID,
-		RemoteRouter: &warptypes.RemoteRouter{
-			ReceiverDomain:   domain,
-			ReceiverContract: receiverContract,
-			Gas:              math.ZeroInt(),
-		},
+// VerifyZKProof verifies the provided groth16 proof and public values locally against the verifying keys of the
+// zk execution ism with the provided identifier. The program verifying key commitment is selected by the proof kind,
+// either the state transition or the state membership program.
+func VerifyZKProof(ctx context.Context, zkismQueryClient zkismtypes.QueryClient, ismID util.HexAddress, kind string, proof, publicValues []byte) error {
+	res, err := zkismQueryClient.Ism(ctx, &zkismtypes.QueryIsmRequest{Id: ismID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to query zk ism: %w", err)
+	}
+
+	var programVkey []byte
+	switch kind {
+	case proofKindStateTransition:
+		programVkey = res.Ism.StateTransitionVkey
+	case proofKindStateMembership:
+		programVkey = res.Ism.StateMembershipVkey
+	default:
+		return fmt.Errorf("unknown proof kind %q, expected %q or %q", kind, proofKindStateTransition, proofKindStateMembership)
 	}
 
-	res := broadcaster.BroadcastTx(ctx, &msgEnrollRemoteRouter)
-	recvContract := parseReceiverContractFromEvents(res.Events)
+	verifier, err := zkismtypes.NewSP1Groth16Verifier(res.Ism.Groth16Vkey)
+	if err != nil {
+		return err
+	}
 
-	fmt.Printf("successfully registered remote router on Hyperlane cosmosnative: \n%s", recvContract)
+	return verifier.VerifyProof(proof, programVkey, publicValues)
+}
+
+// UpdateZKIsm advances the trusted state of the zk execution ism with the provided identifier to the latest EVM
+// block ev-node reports as included on Celestia, using the provided state transition proof. The proof is verified
+// locally and the heights committed in its public values are checked against the ism and ev-node before it is
+// broadcast, and the updated state root is compared against the EVM block afterwards.
+func UpdateZKIsm(ctx context.Context, broadcaster *broadcaster.Broadcaster, ethClient *ethclient.Client, evnodeClient *evclient.Client, ismID util.HexAddress, proof, publicValues []byte) error {
+	zkismQueryClient := zkismtypes.NewQueryClient(broadcaster.Conn())
+
+	before, err := zkismQueryClient.Ism(ctx, &zkismtypes.QueryIsmRequest{Id: ismID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to query zk ism: %w", err)
+	}
+
+	trustedHeight, err := getDAIncludedHeight(ctx, evnodeClient)
+	if err != nil {
+		return fmt.Errorf("failed to get DA included height: %w", err)
+	}
+
+	if trustedHeight <= before.Ism.Height {
+		return fmt.Errorf("ism trusted height %d is already at or beyond the DA included height %d", before.Ism.Height, trustedHeight)
+	}
+
+	celestiaHeight, err := getCelestiaInclusionHeight(ctx, evnodeClient, trustedHeight)
+	if err != nil {
+		return err
+	}
+
+	if err := VerifyZKProof(ctx, zkismQueryClient, ismID, proofKindStateTransition, proof, publicValues); err != nil {
+		return fmt.Errorf("proof verification failed: %w", err)
+	}
+
+	outputs, err := decodeStateTransitionOutputs(publicValues)
+	if err != nil {
+		return err
+	}
+
+	// a valid proof of another range would be rejected by the module or move the ism to a state other than the
+	// one reported by ev-node, so the committed heights are checked before any tx is broadcast
+	if outputs.TrustedHeight != before.Ism.Height || outputs.PrevCelestiaHeight != before.Ism.CelestiaHeight {
+		return fmt.Errorf("proof starts at height %d (celestia height %d), expected the ism trusted height %d (celestia height %d)", outputs.TrustedHeight, outputs.PrevCelestiaHeight, before.Ism.Height, before.Ism.CelestiaHeight)
+	}
+
+	if outputs.NewHeight != trustedHeight || outputs.CelestiaHeight != celestiaHeight {
+		return fmt.Errorf("proof ends at height %d (celestia height %d), expected the DA included height %d (celestia height %d)", outputs.NewHeight, outputs.CelestiaHeight, trustedHeight, celestiaHeight)
+	}
+
+	// Height carries the Celestia height at which the new trusted EVM block was included, committed as
+	// celestia_height in the public values, not the EVM block height, which the ism takes from new_height.
+	msgUpdateZKExecutionISM := zkismtypes.MsgUpdateZKExecutionISM{
+		Id:           ismID,
+		Height:       celestiaHeight,
+		Proof:        proof,
+		PublicValues: publicValues,
+		Signer:       broadcaster.Address().String(),
+	}
+
+	if _, err := broadcaster.BroadcastTx(ctx, &msgUpdateZKExecutionISM); err != nil {
+		return err
+	}
+
+	after, err := zkismQueryClient.Ism(ctx, &zkismtypes.QueryIsmRequest{Id: ismID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to query zk ism: %w", err)
+	}
+
+	fmt.Printf("old trusted height: %d (celestia height %d)\n", before.Ism.Height, before.Ism.CelestiaHeight)
+	fmt.Printf("new trusted height: %d (celestia height %d)\n", after.Ism.Height, after.Ism.CelestiaHeight)
+
+	header, err := ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(after.Ism.Height))
+	if err != nil {
+		return fmt.Errorf("failed to get evm block %d: %w", after.Ism.Height, err)
+	}
+
+	if !bytes.Equal(header.Root.Bytes(), after.Ism.StateRoot) {
+		slog.Warn("ism trusted state root differs from the evm block state root", "height", after.Ism.Height, "ism_state_root", hex.EncodeToString(after.Ism.StateRoot), "evm_state_root", header.Root.Hex())
+	}
+
+	return nil
+}
+
+// CheckRootConsistency compares the state root of the EVM block at the provided height against the trusted
+// state root of the zk execution ism with the provided identifier and reports whether they match.
+func CheckRootConsistency(ctx context.Context, ethClient *ethclient.Client, zkismQueryClient zkismtypes.QueryClient, ismID util.HexAddress, height uint64) (bool, error) {
+	res, err := zkismQueryClient.Ism(ctx, &zkismtypes.QueryIsmRequest{Id: ismID.String()})
+	if err != nil {
+		return false, fmt.Errorf("failed to query zk ism: %w", err)
+	}
+
+	header, err := ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return false, fmt.Errorf("failed to get evm block %d: %w", height, err)
+	}
+
+	fmt.Printf("evm state root at height %d: %x\n", height, header.Root.Bytes())
+	fmt.Printf("ism trusted state root at height %d: %x\n", res.Ism.Height, res.Ism.StateRoot)
+
+	if res.Ism.Height != height {
+		fmt.Printf("MISMATCH: ism trusted height %d differs from requested height %d\n", res.Ism.Height, height)
+		return false, nil
+	}
+
+	if !bytes.Equal(header.Root.Bytes(), res.Ism.StateRoot) {
+		fmt.Println("MISMATCH: state roots differ")
+		return false, nil
+	}
+
+	fmt.Println("MATCH: state roots are consistent")
+	return true, nil
+}
+
+// zkIsmParamsFromFlags resolves the zk ism circuit parameters from the zk ism flags, falling back to the testdata
+// verifying keys and the canonical namespace. All values are validated so that malformed input is rejected before
+// any transaction is broadcast.
+func zkIsmParamsFromFlags() (ZKIsmParams, error) {
+	var (
+		params ZKIsmParams
+		err    error
+	)
+
+	if params.Groth16Vkey, err = readGroth16Vkey(groth16VkeyPath); err != nil {
+		return ZKIsmParams{}, err
+	}
+
+	if stateVkeyHex != "" {
+		params.StateTransitionVkey, err = decodeHexParam("--state-vkey", stateVkeyHex, 32)
+	} else {
+		params.StateTransitionVkey, err = readStateTransitionVkey()
+	}
+	if err != nil {
+		return ZKIsmParams{}, err
+	}
+
+	if messageVkeyHex != "" {
+		params.StateMembershipVkey, err = decodeHexParam("--message-vkey", messageVkeyHex, 32)
+	} else {
+		params.StateMembershipVkey, err = readStateMembershipVkey()
+	}
+	if err != nil {
+		return ZKIsmParams{}, err
+	}
+
+	if params.Namespace, err = decodeHexParam("--namespace", zkIsmNamespaceHex, 29); err != nil {
+		return ZKIsmParams{}, err
+	}
+
+	if sequencerPubKeyHex != "" {
+		if params.SequencerPubKey, err = decodeHexParam("--sequencer-pubkey", sequencerPubKeyHex, 32); err != nil {
+			return ZKIsmParams{}, err
+		}
+	}
+
+	return params, nil
+}
+
+// decodeHexParam decodes the optionally 0x-prefixed hex value of the named flag, requiring the provided length.
+func decodeHexParam(name, value string, length int) ([]byte, error) {
+	bz, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+
+	if len(bz) != length {
+		return nil, fmt.Errorf("invalid %s %q: expected %d bytes, got %d", name, value, length, len(bz))
+	}
+
+	return bz, nil
+}
+
+// dialExecutionClients returns clients for the EVM and ev-node RPCs at the provided addresses after checking both
+// respond within --timeout. Dialing either succeeds lazily, so an unreachable endpoint would otherwise only surface
+// after transactions have been broadcast.
+func dialExecutionClients(ctx context.Context, evmRpcAddr, evnodeRpcAddr string) (*ethclient.Client, *evclient.Client, error) {
+	ethClient, err := ethclient.Dial(fmt.Sprintf("http://%s", evmRpcAddr))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to EVM RPC %s: %w", evmRpcAddr, err)
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, confirmTimeout)
+	defer cancel()
+
+	if _, err := ethClient.ChainID(probeCtx); err != nil {
+		return nil, nil, fmt.Errorf("EVM RPC %s is unreachable: %w", evmRpcAddr, err)
+	}
+
+	evnodeClient := evclient.NewClient(fmt.Sprintf("http://%s", evnodeRpcAddr))
+
+	health, err := evnodeClient.GetHealth(probeCtx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ev-node RPC %s is unreachable: %w", evnodeRpcAddr, err)
+	}
+
+	if health == evpb.HealthStatus_FAIL {
+		return nil, nil, fmt.Errorf("ev-node RPC %s reports status %s", evnodeRpcAddr, health)
+	}
+
+	return ethClient, evnodeClient, nil
+}
+
+// getDAIncludedHeight returns the height up to which ev-node reports all blocks as included on Celestia.
+func getDAIncludedHeight(ctx context.Context, client *evclient.Client) (uint64, error) {
+	bz, err := client.GetMetadata(ctx, daIncludedHeightKey)
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeDAIncludedHeight(bz)
+}
+
+// decodeDAIncludedHeight decodes the da included height stored by ev-node, which writes it as an 8-byte little
+// endian integer with binary.LittleEndian.PutUint64 when advancing it (incrementDAIncludedHeight in
+// block/da_includer.go of github.com/evstack/ev-node v1.0.0-beta.5).
+func decodeDAIncludedHeight(bz []byte) (uint64, error) {
+	if len(bz) != 8 {
+		return 0, fmt.Errorf("unexpected da included height length: %d", len(bz))
+	}
+
+	height := binary.LittleEndian.Uint64(bz)
+	if height == 0 {
+		return 0, fmt.Errorf("no blocks have been included on celestia yet")
+	}
+
+	return height, nil
+}
+
+// stateTransitionOutputs holds the heights committed in the public values of a state transition proof.
+type stateTransitionOutputs struct {
+	PrevCelestiaHeight uint64
+	CelestiaHeight     uint64
+	TrustedHeight      uint64
+	NewHeight          uint64
+}
+
+// stateTransitionOutputsLen is the length of the public values of a state transition proof: the
+// BlockRangeExecOutput of crates/ev-zkevm-types, bincode encoded by the ev-range-exec program with fixed size
+// little endian integers and byte arrays without length prefix.
+const stateTransitionOutputsLen = 32 + 8 + 32 + 8 + 8 + 32 + 8 + 32 + 29 + 32
+
+// decodeStateTransitionOutputs decodes the heights committed in the provided state transition proof public values.
+// The byte offsets follow the field order of BlockRangeExecOutput: prev_celestia_header_hash, prev_celestia_height,
+// celestia_header_hash, celestia_height, trusted_height, trusted_state_root, new_height, new_state_root, namespace
+// and public_key.
+func decodeStateTransitionOutputs(publicValues []byte) (stateTransitionOutputs, error) {
+	if len(publicValues) != stateTransitionOutputsLen {
+		return stateTransitionOutputs{}, fmt.Errorf("unexpected state transition public values length: %d, expected %d", len(publicValues), stateTransitionOutputsLen)
+	}
+
+	return stateTransitionOutputs{
+		PrevCelestiaHeight: binary.LittleEndian.Uint64(publicValues[32:40]),
+		CelestiaHeight:     binary.LittleEndian.Uint64(publicValues[72:80]),
+		TrustedHeight:      binary.LittleEndian.Uint64(publicValues[80:88]),
+		NewHeight:          binary.LittleEndian.Uint64(publicValues[120:128]),
+	}, nil
+}
+
+// getCelestiaInclusionHeight returns the Celestia height at which both the header and data of the provided
+// block were included, as reported by ev-node.
+func getCelestiaInclusionHeight(ctx context.Context, client *evclient.Client, height uint64) (uint64, error) {
+	resp, err := client.GetBlockByHeight(ctx, height)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block %d from ev-node: %w", height, err)
+	}
+
+	daHeight := max(resp.HeaderDaHeight, resp.DataDaHeight)
+	if daHeight == 0 {
+		return 0, fmt.Errorf("block %d has not been included on celestia", height)
+	}
+
+	return daHeight, nil
 }
 
 func getSequencerPubKey(ctx context.Context, client *evclient.Client) ([]byte, error) {
@@ -187,89 +2003,145 @@ func getSequencerPubKey(ctx context.Context, client *evclient.Client) ([]byte, e
 	return resp.Block.Header.Signer.PubKey[4:], nil
 }
 
-func readGroth16Vkey() []byte {
-	groth16Vkey, err := os.ReadFile("testdata/vkeys/groth16_vk.bin")
+// readGroth16Vkey reads the groth16 verifying key from the provided path, falling back to the testdata key when the
+// path is empty and the testdata key exists relative to the working directory.
+func readGroth16Vkey(path string) ([]byte, error) {
+	if path == "" {
+		if _, err := os.Stat(defaultGroth16VkeyPath); err != nil {
+			return nil, fmt.Errorf("no groth16 verifying key found at %s: set --groth16-vkey", defaultGroth16VkeyPath)
+		}
+		path = defaultGroth16VkeyPath
+	}
+
+	groth16Vkey, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to read groth16 verifying key: %w", err)
 	}
 
-	return groth16Vkey
+	return groth16Vkey, nil
 }
 
-func readStateTransitionVkey() []byte {
+func readStateTransitionVkey() ([]byte, error) {
 	data, err := os.ReadFile("testdata/vkeys/ev-combined-vkey-hash")
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	hashStr := strings.TrimSpace(string(data))
 	hashBz, err := hex.DecodeString(strings.TrimPrefix(hashStr, "0x"))
 	if err != nil {
-		log.Fatalf("failed to decode hex: %v", err)
+		return nil, fmt.Errorf("failed to decode hex: %w", err)
 	}
 
-	return hashBz
+	return hashBz, nil
 }
 
-func readStateMembershipVkey() []byte {
+func readStateMembershipVkey() ([]byte, error) {
 	data, err := os.ReadFile("testdata/vkeys/ev-hyperlane-vkey-hash")
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	hashStr := strings.TrimSpace(string(data))
 	hashBz, err := hex.DecodeString(strings.TrimPrefix(hashStr, "0x"))
 	if err != nil {
-		log.Fatalf("failed to decode hex: %v", err)
+		return nil, fmt.Errorf("failed to decode hex: %w", err)
 	}
 
-	return hashBz
+	return hashBz, nil
 }
 
-func writeConfig(cfg *HyperlaneConfig) {
+func writeConfig(cfg *HyperlaneConfig) error {
+	cfg.Build = currentBuildInfo()
+
 	out, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		log.Fatalf("failed to marshal config: %v", err)
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	path := configOutputPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
 	}
 
-	outputPath := "hyperlane-cosmosnative.json"
-	if err := os.WriteFile(outputPath, out, 0o644); err != nil {
-		log.Fatalf("failed to write JSON file: %v", err)
+	if output == outputJSON {
+		fmt.Println(string(out))
+		return nil
 	}
 
 	fmt.Printf("successfully deployed Hyperlane: \n%s\n", string(out))
+	return nil
 }
 
-func GetCelestiaBlockHashAndHeight(ctx context.Context, rpcAddr string) ([32]byte, uint64) {
-	client, err := rpcclient.New(rpcAddr, "/websocket")
+// readBytesFile reads the file at the provided path, decoding it as hex if it is hex encoded text.
+func readBytesFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatalf("failed to connect to Celestia RPC: %v", err)
+		return nil, err
 	}
-	defer client.Stop()
 
-	status, err := client.Status(ctx)
+	if bz, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x")); err == nil {
+		return bz, nil
+	}
+
+	return data, nil
+}
+
+// configOutputPath returns the path the deployed HyperlaneConfig is written to, --config-out if set.
+func configOutputPath() string {
+	if configOut != "" {
+		return configOut
+	}
+
+	return filepath.Join(outputDir, configFileName)
+}
+
+// evmAddress returns the checksummed 20-byte EVM form of the provided hyperlane address, i.e. its last 20 bytes.
+func evmAddress(addr util.HexAddress) string {
+	return common.BytesToAddress(addr.Bytes()[util.HEX_ADDRESS_LENGTH-common.AddressLength:]).Hex()
+}
+
+func readConfig(path string) (*HyperlaneConfig, error) {
+	bz, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatalf("failed to get Celestia status: %v", err)
+		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	height := uint64(status.SyncInfo.LatestBlockHeight)
-	heightInt64 := int64(height)
+	var cfg HyperlaneConfig
+	if err := json.Unmarshal(bz, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// GetCelestiaBlockHash returns the header hash of the Celestia block at the provided height.
+func GetCelestiaBlockHash(ctx context.Context, rpcAddr string, height uint64) ([32]byte, error) {
+	var hash [32]byte
 
+	client, err := rpcclient.New(rpcAddr, "/websocket")
+	if err != nil {
+		return hash, fmt.Errorf("failed to connect to Celestia RPC: %w", err)
+	}
+	defer client.Stop()
+
+	heightInt64 := int64(height)
 	block, err := client.Block(ctx, &heightInt64)
 	if err != nil {
-		log.Fatalf("failed to fetch block at height %d: %v", height, err)
+		return hash, fmt.Errorf("failed to fetch block at height %d: %w", height, err)
 	}
 
 	blockHash := block.BlockID.Hash.Bytes()
-
-	var hash [32]byte
 	if len(blockHash) != 32 {
-		log.Fatalf("unexpected block hash length: %d", len(blockHash))
+		return hash, fmt.Errorf("unexpected block hash length: %d", len(blockHash))
 	}
 	copy(hash[:], blockHash)
 
-	fmt.Printf("Celestia node height: %d\nBlock header hash: 0x%s\n",
-		height, hex.EncodeToString(hash[:]))
+	slog.Info("celestia block hash", "height", height, "hash", hex.EncodeToString(hash[:]))
 
-	return hash, height
+	return hash, nil
 }
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDecodeDAIncludedHeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		bz      []byte
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name: "height 1",
+			bz:   []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			want: 1,
+		},
+		{
+			// 0x0000000000123456, least significant byte first
+			name: "height 1193046",
+			bz:   []byte{0x56, 0x34, 0x12, 0x00, 0x00, 0x00, 0x00, 0x00},
+			want: 1193046,
+		},
+		{
+			name:    "nothing included yet",
+			bz:      make([]byte, 8),
+			wantErr: true,
+		},
+		{
+			name:    "short value",
+			bz:      []byte{0x01, 0x00, 0x00, 0x00},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeDAIncludedHeight(tt.bz)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got height %d", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got height %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeStateTransitionOutputs(t *testing.T) {
+	// public values of the ev-range-exec proof in the repository testdata
+	publicValues, err := os.ReadFile("../../../../testdata/sp1-inputs.bin")
+	if err != nil {
+		t.Fatalf("failed to read public values: %v", err)
+	}
+
+	got, err := decodeStateTransitionOutputs(publicValues)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := stateTransitionOutputs{PrevCelestiaHeight: 29, CelestiaHeight: 31, TrustedHeight: 97, NewHeight: 102}
+	if got != want {
+		t.Fatalf("got outputs %+v, want %+v", got, want)
+	}
+
+	if _, err := decodeStateTransitionOutputs(publicValues[:len(publicValues)-1]); err == nil {
+		t.Fatal("expected an error for truncated public values")
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+const (
+	// logFormatText and logFormatJSON are the values accepted by --log-format.
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// setupLogger installs the default slog logger writing to stderr at the --log-level in the --log-format.
+func setupLogger() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(logLevel)); err != nil {
+		return fmt.Errorf("invalid --log-level %q, expected debug, info, warn or error", logLevel)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch logFormat {
+	case logFormatText:
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	case logFormatJSON:
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return fmt.Errorf("unknown log format %q, expected %q or %q", logFormat, logFormatText, logFormatJSON)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// documentWriter writes each document to the file at path, replacing its contents.
+type documentWriter struct {
+	path string
+}
+
+func (w documentWriter) Write(p []byte) (int, error) {
+	if err := os.WriteFile(w.path, p, 0o644); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// documentOutput returns the writer generated and signed tx documents are written to, the file named by
+// --output-document or stdout.
+func documentOutput() io.Writer {
+	if outputDocument == "" {
+		return os.Stdout
+	}
+
+	return documentWriter{path: outputDocument}
+}
+
+// writeDocument writes the provided document to the --output-document file or stdout.
+func writeDocument(bz []byte) error {
+	if _, err := documentOutput().Write(append(bz, '\n')); err != nil {
+		return fmt.Errorf("failed to write document: %w", err)
+	}
+
+	return nil
+}
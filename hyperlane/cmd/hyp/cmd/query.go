@@ -0,0 +1,445 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bcp-innovations/hyperlane-cosmos/util"
+	ismtypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/01_interchain_security/types"
+	coretypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/types"
+	warptypes "github.com/bcp-innovations/hyperlane-cosmos/x/warp/types"
+	"github.com/celestiaorg/celestia-app/v6/app/encoding"
+	zkismtypes "github.com/celestiaorg/celestia-app/v6/x/zkism/types"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/cosmos/gogoproto/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// outputText and outputJSON select the output format of the query commands.
+	outputText = "text"
+	outputJSON = "json"
+
+	// ismTypeZKExecution is the ism type reported for zk execution isms of the zkism module.
+	ismTypeZKExecution = "ZKExecution"
+)
+
+// mailboxInfo is the output form of a mailbox returned by the query mailboxes command.
+type mailboxInfo struct {
+	ID              util.HexAddress `json:"id"`
+	Owner           string          `json:"owner"`
+	LocalDomain     uint32          `json:"local_domain"`
+	DefaultIsm      util.HexAddress `json:"default_ism"`
+	DefaultHook     string          `json:"default_hook"`
+	RequiredHook    string          `json:"required_hook"`
+	MessageSent     uint32          `json:"message_sent"`
+	MessageReceived uint32          `json:"message_received"`
+}
+
+// tokenInfo is the output form of a warp token returned by the query tokens command.
+type tokenInfo struct {
+	ID            string             `json:"id"`
+	Type          string             `json:"type"`
+	OriginDenom   string             `json:"origin_denom"`
+	OriginMailbox string             `json:"origin_mailbox"`
+	Owner         string             `json:"owner"`
+	IsmID         string             `json:"ism_id"`
+	RemoteRouters []remoteRouterInfo `json:"remote_routers"`
+}
+
+// remoteRouterInfo is the output form of a remote router enrolled on a warp token.
+type remoteRouterInfo struct {
+	ReceiverDomain   uint32 `json:"receiver_domain"`
+	ReceiverContract string `json:"receiver_contract"`
+}
+
+// ismInfo is the output form of an ism returned by the query isms command. Only the fields relevant to the
+// ism type are set.
+type ismInfo struct {
+	ID    util.HexAddress `json:"id"`
+	Type  string          `json:"type"`
+	Owner string          `json:"owner"`
+
+	// multisig isms
+	Validators []string `json:"validators,omitempty"`
+	Threshold  uint32   `json:"threshold,omitempty"`
+
+	// zk execution isms, the groth16 vkey is reported as the sha256 hash of the verifying key
+	StateTransitionVkey string `json:"state_transition_vkey,omitempty"`
+	StateMembershipVkey string `json:"state_membership_vkey,omitempty"`
+	Groth16VkeyHash     string `json:"groth16_vkey_hash,omitempty"`
+	Namespace           string `json:"namespace,omitempty"`
+	SequencerPublicKey  string `json:"sequencer_public_key,omitempty"`
+	Height              uint64 `json:"height,omitempty"`
+	StateRoot           string `json:"state_root,omitempty"`
+	CelestiaHeight      uint64 `json:"celestia_height,omitempty"`
+	CelestiaHeaderHash  string `json:"celestia_header_hash,omitempty"`
+}
+
+// QueryMailboxes returns all mailboxes deployed on chain, following pagination.
+func QueryMailboxes(ctx context.Context, hypQueryClient coretypes.QueryClient) ([]coretypes.Mailbox, error) {
+	var (
+		mailboxes []coretypes.Mailbox
+		nextKey   []byte
+	)
+
+	for {
+		res, err := hypQueryClient.Mailboxes(ctx, &coretypes.QueryMailboxesRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query mailboxes: %w", err)
+		}
+
+		mailboxes = append(mailboxes, res.Mailboxes...)
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return mailboxes, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// PrintMailboxes prints the provided mailboxes as a table or as JSON, depending on the output format.
+func PrintMailboxes(mailboxes []coretypes.Mailbox, output string) error {
+	infos := make([]mailboxInfo, len(mailboxes))
+	for i, mailbox := range mailboxes {
+		infos[i] = mailboxInfo{
+			ID:              mailbox.Id,
+			Owner:           mailbox.Owner,
+			LocalDomain:     mailbox.LocalDomain,
+			DefaultIsm:      mailbox.DefaultIsm,
+			DefaultHook:     optionalHexAddress(mailbox.DefaultHook),
+			RequiredHook:    optionalHexAddress(mailbox.RequiredHook),
+			MessageSent:     mailbox.MessageSent,
+			MessageReceived: mailbox.MessageReceived,
+		}
+	}
+
+	switch output {
+	case outputJSON:
+		return printJSON(infos)
+	case outputText:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tLOCAL DOMAIN\tDEFAULT ISM\tDEFAULT HOOK\tREQUIRED HOOK\tSENT\tRECEIVED")
+		for _, info := range infos {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%d\t%d\n", info.ID, info.LocalDomain, info.DefaultIsm, info.DefaultHook, info.RequiredHook, info.MessageSent, info.MessageReceived)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q, expected %q or %q", output, outputText, outputJSON)
+	}
+}
+
+// QueryTokens returns all warp tokens deployed on chain along with their enrolled remote routers. If tokenID is
+// set only that token is returned.
+func QueryTokens(ctx context.Context, warpQueryClient warptypes.QueryClient, tokenID string) ([]tokenInfo, error) {
+	var tokens []warptypes.WrappedHypToken
+	if tokenID != "" {
+		res, err := warpQueryClient.Token(ctx, &warptypes.QueryTokenRequest{Id: tokenID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query token %s: %w", tokenID, err)
+		}
+
+		tokens = append(tokens, *res.Token)
+	} else {
+		var nextKey []byte
+		for {
+			res, err := warpQueryClient.Tokens(ctx, &warptypes.QueryTokensRequest{Pagination: &query.PageRequest{Key: nextKey}})
+			if err != nil {
+				return nil, fmt.Errorf("failed to query tokens: %w", err)
+			}
+
+			tokens = append(tokens, res.Tokens...)
+
+			if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+				break
+			}
+			nextKey = res.Pagination.NextKey
+		}
+	}
+
+	infos := make([]tokenInfo, len(tokens))
+	for i, token := range tokens {
+		routers, err := queryRemoteRouters(ctx, warpQueryClient, token.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		infos[i] = tokenInfo{
+			ID:            token.Id,
+			Type:          tokenTypeName(token.TokenType),
+			OriginDenom:   token.OriginDenom,
+			OriginMailbox: token.OriginMailbox,
+			Owner:         token.Owner,
+			IsmID:         optionalHexAddress(token.IsmId),
+			RemoteRouters: routers,
+		}
+	}
+
+	return infos, nil
+}
+
+// queryRemoteRouters returns the remote routers enrolled on the token with the provided identifier.
+func queryRemoteRouters(ctx context.Context, warpQueryClient warptypes.QueryClient, tokenID string) ([]remoteRouterInfo, error) {
+	routers := []remoteRouterInfo{}
+
+	var nextKey []byte
+	for {
+		res, err := warpQueryClient.RemoteRouters(ctx, &warptypes.QueryRemoteRoutersRequest{Id: tokenID, Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query remote routers of token %s: %w", tokenID, err)
+		}
+
+		for _, router := range res.RemoteRouters {
+			routers = append(routers, remoteRouterInfo{
+				ReceiverDomain:   router.ReceiverDomain,
+				ReceiverContract: router.ReceiverContract,
+			})
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return routers, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// PrintTokens prints the provided tokens and their remote routers as text or as JSON, depending on the output format.
+func PrintTokens(tokens []tokenInfo, output string) error {
+	switch output {
+	case outputJSON:
+		return printJSON(tokens)
+	case outputText:
+		for _, token := range tokens {
+			fmt.Printf("token %s\n", token.ID)
+			fmt.Printf("  type:           %s\n", token.Type)
+			fmt.Printf("  origin denom:   %s\n", token.OriginDenom)
+			fmt.Printf("  origin mailbox: %s\n", token.OriginMailbox)
+			fmt.Printf("  owner:          %s\n", token.Owner)
+			fmt.Printf("  ism:            %s\n", token.IsmID)
+
+			if len(token.RemoteRouters) == 0 {
+				fmt.Println("  remote routers: none")
+				continue
+			}
+
+			fmt.Println("  remote routers:")
+			for _, router := range token.RemoteRouters {
+				fmt.Printf("    domain %d: %s\n", router.ReceiverDomain, router.ReceiverContract)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, expected %q or %q", output, outputText, outputJSON)
+	}
+}
+
+// tokenTypeName returns the short name of the provided warp token type.
+func tokenTypeName(tokenType warptypes.HypTokenType) string {
+	switch tokenType {
+	case warptypes.HYP_TOKEN_TYPE_COLLATERAL:
+		return "collateral"
+	case warptypes.HYP_TOKEN_TYPE_SYNTHETIC:
+		return "synthetic"
+	default:
+		return tokenType.String()
+	}
+}
+
+// QueryIsms returns all isms deployed on chain, including the zk execution isms of the zkism module. If ismID is
+// set only that ism is returned.
+func QueryIsms(ctx context.Context, enc encoding.Config, ismQueryClient ismtypes.QueryClient, zkismQueryClient zkismtypes.QueryClient, ismID string) ([]ismInfo, error) {
+	if ismID != "" {
+		zkRes, err := zkismQueryClient.Ism(ctx, &zkismtypes.QueryIsmRequest{Id: ismID})
+		if err == nil {
+			return []ismInfo{zkIsmInfo(zkRes.Ism)}, nil
+		}
+
+		// only an ism unknown to the zkism module may be a core ism, other errors are not hidden by the core query
+		if status.Code(err) != codes.NotFound {
+			return nil, fmt.Errorf("failed to query zk ism %s: %w", ismID, err)
+		}
+
+		res, err := ismQueryClient.Ism(ctx, &ismtypes.QueryIsmRequest{Id: ismID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query ism %s: %w", ismID, err)
+		}
+
+		info, err := coreIsmInfo(enc, &res.Ism)
+		if err != nil {
+			return nil, err
+		}
+
+		return []ismInfo{info}, nil
+	}
+
+	var (
+		infos   []ismInfo
+		nextKey []byte
+	)
+
+	for {
+		res, err := ismQueryClient.Isms(ctx, &ismtypes.QueryIsmsRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query isms: %w", err)
+		}
+
+		for _, ism := range res.Isms {
+			info, err := coreIsmInfo(enc, ism)
+			if err != nil {
+				return nil, err
+			}
+
+			infos = append(infos, info)
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = res.Pagination.NextKey
+	}
+
+	nextKey = nil
+	for {
+		res, err := zkismQueryClient.Isms(ctx, &zkismtypes.QueryIsmsRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query zk isms: %w", err)
+		}
+
+		for _, ism := range res.Isms {
+			infos = append(infos, zkIsmInfo(ism))
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return infos, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// coreIsmInfo decodes the provided ism of the core interchain security module.
+func coreIsmInfo(enc encoding.Config, ism *codectypes.Any) (ismInfo, error) {
+	switch ism.TypeUrl {
+	case "/" + proto.MessageName(&ismtypes.NoopISM{}):
+		var noop ismtypes.NoopISM
+		if err := enc.Codec.Unmarshal(ism.Value, &noop); err != nil {
+			return ismInfo{}, fmt.Errorf("unmarshal ism: %w", err)
+		}
+
+		return ismInfo{ID: noop.Id, Type: "Noop", Owner: noop.Owner}, nil
+	case "/" + proto.MessageName(&ismtypes.MerkleRootMultisigISM{}):
+		var multisig ismtypes.MerkleRootMultisigISM
+		if err := enc.Codec.Unmarshal(ism.Value, &multisig); err != nil {
+			return ismInfo{}, fmt.Errorf("unmarshal ism: %w", err)
+		}
+
+		return ismInfo{ID: multisig.Id, Type: "MerkleRootMultisig", Owner: multisig.Owner, Validators: multisig.Validators, Threshold: multisig.Threshold}, nil
+	case "/" + proto.MessageName(&ismtypes.MessageIdMultisigISM{}):
+		var multisig ismtypes.MessageIdMultisigISM
+		if err := enc.Codec.Unmarshal(ism.Value, &multisig); err != nil {
+			return ismInfo{}, fmt.Errorf("unmarshal ism: %w", err)
+		}
+
+		return ismInfo{ID: multisig.Id, Type: "MessageIdMultisig", Owner: multisig.Owner, Validators: multisig.Validators, Threshold: multisig.Threshold}, nil
+	case "/" + proto.MessageName(&ismtypes.RoutingISM{}):
+		var routing ismtypes.RoutingISM
+		if err := enc.Codec.Unmarshal(ism.Value, &routing); err != nil {
+			return ismInfo{}, fmt.Errorf("unmarshal ism: %w", err)
+		}
+
+		return ismInfo{ID: routing.Id, Type: "Routing", Owner: routing.Owner}, nil
+	default:
+		return ismInfo{}, fmt.Errorf("unknown ism type %s", ism.TypeUrl)
+	}
+}
+
+// QueryZKIsm returns the zk execution ism with the provided id.
+func QueryZKIsm(ctx context.Context, zkismQueryClient zkismtypes.QueryClient, ismID string) (ismInfo, error) {
+	res, err := zkismQueryClient.Ism(ctx, &zkismtypes.QueryIsmRequest{Id: ismID})
+	if err != nil {
+		return ismInfo{}, fmt.Errorf("failed to query zk ism %s: %w", ismID, err)
+	}
+
+	return zkIsmInfo(res.Ism), nil
+}
+
+// zkIsmInfo returns the output form of the provided zk execution ism.
+func zkIsmInfo(ism zkismtypes.ZKExecutionISM) ismInfo {
+	groth16VkeyHash := sha256.Sum256(ism.Groth16Vkey)
+
+	return ismInfo{
+		ID:                  ism.Id,
+		Type:                ismTypeZKExecution,
+		Owner:               ism.Owner,
+		StateTransitionVkey: "0x" + hex.EncodeToString(ism.StateTransitionVkey),
+		StateMembershipVkey: "0x" + hex.EncodeToString(ism.StateMembershipVkey),
+		Groth16VkeyHash:     "0x" + hex.EncodeToString(groth16VkeyHash[:]),
+		Namespace:           "0x" + hex.EncodeToString(ism.Namespace),
+		SequencerPublicKey:  "0x" + hex.EncodeToString(ism.SequencerPublicKey),
+		Height:              ism.Height,
+		StateRoot:           "0x" + hex.EncodeToString(ism.StateRoot),
+		CelestiaHeight:      ism.CelestiaHeight,
+		CelestiaHeaderHash:  "0x" + hex.EncodeToString(ism.CelestiaHeaderHash),
+	}
+}
+
+// PrintIsms prints the provided isms as text or as JSON, depending on the output format.
+func PrintIsms(isms []ismInfo, output string) error {
+	switch output {
+	case outputJSON:
+		return printJSON(isms)
+	case outputText:
+		for _, ism := range isms {
+			fmt.Printf("ism %s\n", ism.ID)
+			fmt.Printf("  type:  %s\n", ism.Type)
+			fmt.Printf("  owner: %s\n", ism.Owner)
+
+			if len(ism.Validators) > 0 {
+				fmt.Printf("  threshold:  %d/%d\n", ism.Threshold, len(ism.Validators))
+				fmt.Printf("  validators: %s\n", strings.Join(ism.Validators, ", "))
+			}
+
+			if ism.Type == ismTypeZKExecution {
+				fmt.Printf("  state transition vkey: %s\n", ism.StateTransitionVkey)
+				fmt.Printf("  state membership vkey: %s\n", ism.StateMembershipVkey)
+				fmt.Printf("  groth16 vkey sha256:   %s\n", ism.Groth16VkeyHash)
+				fmt.Printf("  namespace:             %s\n", ism.Namespace)
+				fmt.Printf("  sequencer pubkey:      %s\n", ism.SequencerPublicKey)
+				fmt.Printf("  trusted height:        %d\n", ism.Height)
+				fmt.Printf("  trusted state root:    %s\n", ism.StateRoot)
+				fmt.Printf("  celestia height:       %d\n", ism.CelestiaHeight)
+				fmt.Printf("  celestia header hash:  %s\n", ism.CelestiaHeaderHash)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, expected %q or %q", output, outputText, outputJSON)
+	}
+}
+
+// printJSON prints the provided value as indented JSON.
+func printJSON(v any) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	fmt.Println(string(out))
+	return nil
+}
+
+// optionalHexAddress returns the string form of the provided address, or an empty string if it is unset.
+func optionalHexAddress(addr *util.HexAddress) string {
+	if addr == nil {
+		return ""
+	}
+
+	return addr.String()
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/bcp-innovations/hyperlane-cosmos/util"
+	ismtypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/01_interchain_security/types"
+	hooktypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/02_post_dispatch/types"
+	coretypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/types"
+	warptypes "github.com/bcp-innovations/hyperlane-cosmos/x/warp/types"
+	"github.com/celestiaorg/hyp-deploy/pkg/broadcaster"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// The finders below look up existing components equivalent to the ones a deployment would create, so that
+// re-running a deployment with --reuse-existing picks up where a previous run left off instead of creating duplicates.
+
+// findIf runs find only when reuse is enabled, reporting nothing found otherwise.
+func findIf[T any](reuse bool, find func() (T, bool, error)) (T, bool, error) {
+	if !reuse {
+		var zero T
+		return zero, false, nil
+	}
+
+	return find()
+}
+
+// findNoopIsm returns the first NoopISM owned by owner.
+func findNoopIsm(ctx context.Context, b *broadcaster.Broadcaster, owner string) (util.HexAddress, bool, error) {
+	client := ismtypes.NewQueryClient(b.Conn())
+
+	var nextKey []byte
+	for {
+		res, err := client.Isms(ctx, &ismtypes.QueryIsmsRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return util.HexAddress{}, false, err
+		}
+
+		for _, ism := range res.Isms {
+			if ism.TypeUrl != "/"+proto.MessageName(&ismtypes.NoopISM{}) {
+				continue
+			}
+
+			var noopIsm ismtypes.NoopISM
+			if err := b.Encoding().Codec.Unmarshal(ism.Value, &noopIsm); err != nil {
+				return util.HexAddress{}, false, err
+			}
+
+			if noopIsm.Owner == owner {
+				return noopIsm.Id, true, nil
+			}
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return util.HexAddress{}, false, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// findNoopHook returns the first NoopHook owned by owner.
+func findNoopHook(ctx context.Context, b *broadcaster.Broadcaster, owner string) (util.HexAddress, bool, error) {
+	client := hooktypes.NewQueryClient(b.Conn())
+
+	var nextKey []byte
+	for {
+		res, err := client.NoopHooks(ctx, &hooktypes.QueryNoopHooksRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return util.HexAddress{}, false, err
+		}
+
+		for _, hook := range res.NoopHooks {
+			if hook.Owner == owner {
+				return hook.Id, true, nil
+			}
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return util.HexAddress{}, false, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// findIgp returns the first InterchainGasPaymaster owned by owner charging fees in the provided denom.
+func findIgp(ctx context.Context, b *broadcaster.Broadcaster, owner, denom string) (util.HexAddress, bool, error) {
+	client := hooktypes.NewQueryClient(b.Conn())
+
+	var nextKey []byte
+	for {
+		res, err := client.Igps(ctx, &hooktypes.QueryIgpsRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return util.HexAddress{}, false, err
+		}
+
+		for _, igp := range res.Igps {
+			if igp.Owner == owner && igp.Denom == denom {
+				return igp.Id, true, nil
+			}
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return util.HexAddress{}, false, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// findMailbox returns the first mailbox owned by owner with the provided default ism, hooks and local domain.
+func findMailbox(ctx context.Context, b *broadcaster.Broadcaster, owner string, ismID, hooksID util.HexAddress, localDomain uint32) (util.HexAddress, bool, error) {
+	client := coretypes.NewQueryClient(b.Conn())
+
+	var nextKey []byte
+	for {
+		res, err := client.Mailboxes(ctx, &coretypes.QueryMailboxesRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return util.HexAddress{}, false, err
+		}
+
+		for _, mailbox := range res.Mailboxes {
+			if mailbox.Owner != owner || mailbox.LocalDomain != localDomain || !mailbox.DefaultIsm.Equal(ismID) {
+				continue
+			}
+
+			if mailbox.DefaultHook == nil || !mailbox.DefaultHook.Equal(hooksID) {
+				continue
+			}
+
+			if mailbox.RequiredHook == nil || !mailbox.RequiredHook.Equal(hooksID) {
+				continue
+			}
+
+			return mailbox.Id, true, nil
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return util.HexAddress{}, false, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// findMerkleHookMailbox returns the first mailbox owned by owner with the provided default ism and local domain
+// whose default and required hook is a MerkleTreeHook owned by owner, along with the hook identifier.
+func findMerkleHookMailbox(ctx context.Context, b *broadcaster.Broadcaster, owner string, ismID util.HexAddress, localDomain uint32) (util.HexAddress, util.HexAddress, bool, error) {
+	hookClient := hooktypes.NewQueryClient(b.Conn())
+	coreClient := coretypes.NewQueryClient(b.Conn())
+
+	var nextKey []byte
+	for {
+		res, err := hookClient.MerkleTreeHooks(ctx, &hooktypes.QueryMerkleTreeHooksRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return util.HexAddress{}, util.HexAddress{}, false, err
+		}
+
+		for _, hook := range res.MerkleTreeHooks {
+			if hook.Owner != owner {
+				continue
+			}
+
+			hookID, err := util.DecodeHexAddress(hook.Id)
+			if err != nil {
+				return util.HexAddress{}, util.HexAddress{}, false, err
+			}
+
+			mailboxRes, err := coreClient.Mailbox(ctx, &coretypes.QueryMailboxRequest{Id: hook.MailboxId})
+			if err != nil {
+				return util.HexAddress{}, util.HexAddress{}, false, err
+			}
+
+			mailbox := mailboxRes.Mailbox
+			if mailbox.Owner != owner || mailbox.LocalDomain != localDomain || !mailbox.DefaultIsm.Equal(ismID) {
+				continue
+			}
+
+			if mailbox.DefaultHook == nil || !mailbox.DefaultHook.Equal(hookID) {
+				continue
+			}
+
+			if mailbox.RequiredHook == nil || !mailbox.RequiredHook.Equal(hookID) {
+				continue
+			}
+
+			return mailbox.Id, hookID, true, nil
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return util.HexAddress{}, util.HexAddress{}, false, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// findCollateralToken returns the first collateral token owned by owner for the provided mailbox and denom.
+func findCollateralToken(ctx context.Context, b *broadcaster.Broadcaster, owner string, mailboxID util.HexAddress, originDenom string) (*warptypes.WrappedHypToken, bool, error) {
+	client := warptypes.NewQueryClient(b.Conn())
+
+	var nextKey []byte
+	for {
+		res, err := client.Tokens(ctx, &warptypes.QueryTokensRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, token := range res.Tokens {
+			if token.TokenType != warptypes.HYP_TOKEN_TYPE_COLLATERAL || token.Owner != owner {
+				continue
+			}
+
+			if token.OriginMailbox == mailboxID.String() && token.OriginDenom == originDenom {
+				return &token, true, nil
+			}
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return nil, false, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
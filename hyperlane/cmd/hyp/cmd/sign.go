@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// SignMessage signs the provided data as an ADR-036 off-chain message using the key named keyName in the keyring.
+// It returns the signature and the compressed secp256k1 public key of the signer.
+func SignMessage(kr keyring.Keyring, keyName string, signer sdk.AccAddress, data []byte) ([]byte, []byte, error) {
+	signBytes, err := adr036SignBytes(signer, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sig, pubKey, err := kr.Sign(keyName, signBytes, signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	return sig, pubKey.Bytes(), nil
+}
+
+// VerifyMessage verifies an ADR-036 signature over the provided data, checking that pubKey belongs to signer.
+func VerifyMessage(signer sdk.AccAddress, pubKey, sig, data []byte) error {
+	// the sdk panics when deriving the address of a public key of any other length
+	if len(pubKey) != secp256k1.PubKeySize {
+		return fmt.Errorf("invalid public key length %d, expected a %d-byte compressed secp256k1 key", len(pubKey), secp256k1.PubKeySize)
+	}
+
+	pk := secp256k1.PubKey{Key: pubKey}
+	if !signer.Equals(sdk.AccAddress(pk.Address())) {
+		return fmt.Errorf("public key does not belong to address %s", signer)
+	}
+
+	signBytes, err := adr036SignBytes(signer, data)
+	if err != nil {
+		return err
+	}
+
+	if !pk.VerifySignature(signBytes, sig) {
+		return fmt.Errorf("invalid signature for address %s", signer)
+	}
+
+	return nil
+}
+
+// adr036SignBytes returns the canonical amino JSON sign bytes of an ADR-036 MsgSignData.
+func adr036SignBytes(signer sdk.AccAddress, data []byte) ([]byte, error) {
+	signDoc := map[string]any{
+		"account_number": "0",
+		"chain_id":       "",
+		"fee":            map[string]any{"amount": []any{}, "gas": "0"},
+		"memo":           "",
+		"msgs": []any{
+			map[string]any{
+				"type": "sign/MsgSignData",
+				"value": map[string]any{
+					"data":   base64.StdEncoding.EncodeToString(data),
+					"signer": signer.String(),
+				},
+			},
+		},
+		"sequence": "0",
+	}
+
+	bz, err := json.Marshal(signDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sign doc: %w", err)
+	}
+
+	return sdk.SortJSON(bz)
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ismtypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/01_interchain_security/types"
+	coretypes "github.com/bcp-innovations/hyperlane-cosmos/x/core/types"
+	"github.com/celestiaorg/hyp-deploy/pkg/broadcaster"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// StressDeploy creates count NoopISM stacks using up to concurrency workers and reports the number of
+// successful and failed deployments along with their timings. The mailbox of the i-th stack is created on local
+// domain domainBase+i, so that stacks do not collide on a domain. Individual failures are logged and counted
+// rather than aborting the run, an error is returned at the end if any deployment failed.
+func StressDeploy(ctx context.Context, broadcaster *broadcaster.Broadcaster, count, concurrency int, domainBase uint32) error {
+	var (
+		succeeded, failed atomic.Int64
+		wg                sync.WaitGroup
+	)
+
+	jobs := make(chan int)
+	durations := make([]time.Duration, count)
+
+	start := time.Now()
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				deployStart := time.Now()
+				if err := deployNoopStack(ctx, broadcaster, domainBase+uint32(i)); err != nil {
+					slog.Error("deployment failed", "index", i, "err", err)
+					failed.Add(1)
+					continue
+				}
+
+				durations[i] = time.Since(deployStart)
+				succeeded.Add(1)
+			}
+		}()
+	}
+
+	for i := range count {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	elapsed := time.Since(start)
+
+	var total, slowest time.Duration
+	for _, d := range durations {
+		total += d
+		slowest = max(slowest, d)
+	}
+
+	fmt.Printf("deployments: %d succeeded, %d failed\n", succeeded.Load(), failed.Load())
+	fmt.Printf("elapsed: %s\n", elapsed.Round(time.Millisecond))
+	if n := succeeded.Load(); n > 0 {
+		fmt.Printf("mean deployment time: %s, slowest: %s\n", (total / time.Duration(n)).Round(time.Millisecond), slowest.Round(time.Millisecond))
+	}
+
+	if n := failed.Load(); n > 0 {
+		return fmt.Errorf("%d/%d deployments failed", n, count)
+	}
+
+	return nil
+}
+
+func deployNoopStack(ctx context.Context, broadcaster *broadcaster.Broadcaster, domain uint32) error {
+	msgCreateNoopISM := ismtypes.MsgCreateNoopIsm{
+		Creator: broadcaster.Address().String(),
+	}
+
+	res, err := broadcaster.BroadcastTx(ctx, &msgCreateNoopISM)
+	if err != nil {
+		return err
+	}
+
+	ismID, err := parseIsmIDFromNoopISMEvents(res.Events)
+	if err != nil {
+		return fmt.Errorf("failed to create NoopISM: %w", err)
+	}
+
+	_, err = SetupWithIsm(ctx, broadcaster, ismID, domain, collateralDenom)
+	return err
+}
+
+// nextFreeLocalDomain returns the local domain following the highest local domain used by an existing mailbox.
+func nextFreeLocalDomain(ctx context.Context, hypQueryClient coretypes.QueryClient) (uint32, error) {
+	var (
+		highest uint32
+		nextKey []byte
+	)
+	for {
+		res, err := hypQueryClient.Mailboxes(ctx, &coretypes.QueryMailboxesRequest{Pagination: &query.PageRequest{Key: nextKey}})
+		if err != nil {
+			return 0, fmt.Errorf("failed to query mailboxes: %w", err)
+		}
+
+		for _, mailbox := range res.Mailboxes {
+			highest = max(highest, mailbox.LocalDomain)
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return highest + 1, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// BenchBroadcast submits count bank sends to self using locally managed sequences and waits for all of them to
+// be confirmed, reporting the submission and confirmation throughput. An error is returned if any tx failed to be
+// submitted or confirmed.
+func BenchBroadcast(ctx context.Context, broadcaster *broadcaster.Broadcaster, count int) error {
+	msg := &banktypes.MsgSend{
+		FromAddress: broadcaster.Address().String(),
+		ToAddress:   broadcaster.Address().String(),
+		Amount:      sdk.NewCoins(sdk.NewInt64Coin(denom, 1)),
+	}
+
+	start := time.Now()
+
+	var submitted []*sdk.TxResponse
+	for i := range count {
+		res, err := broadcaster.SignAndBroadcast(ctx, msg)
+		if err != nil {
+			slog.Error("submission failed", "index", i, "err", err)
+			continue
+		}
+
+		submitted = append(submitted, res)
+	}
+
+	submitElapsed := time.Since(start)
+
+	var confirmed int
+	for _, res := range submitted {
+		if _, err := broadcaster.Confirm(ctx, res); err != nil {
+			slog.Error("confirmation failed", "tx_hash", res.TxHash, "err", err)
+			continue
+		}
+
+		confirmed++
+	}
+
+	confirmElapsed := time.Since(start)
+
+	fmt.Printf("submitted: %d/%d in %s (%.2f tx/s)\n", len(submitted), count, submitElapsed.Round(time.Millisecond), float64(len(submitted))/submitElapsed.Seconds())
+	fmt.Printf("confirmed: %d/%d in %s (%.2f tx/s)\n", confirmed, count, confirmElapsed.Round(time.Millisecond), float64(confirmed)/confirmElapsed.Seconds())
+
+	if confirmed < count {
+		return fmt.Errorf("%d/%d txs failed to be submitted or confirmed", count-confirmed, count)
+	}
+
+	return nil
+}
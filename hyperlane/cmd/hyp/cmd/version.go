@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+// Build metadata set at link time, e.g.
+//
+//	go build -ldflags "-X github.com/celestiaorg/hyp-deploy/cmd/hyp/cmd.Version=v1.0.0 \
+//	  -X github.com/celestiaorg/hyp-deploy/cmd/hyp/cmd.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/celestiaorg/hyp-deploy/cmd/hyp/cmd.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// CelestiaAppVersion and HyperlaneCosmosVersion default to the module versions recorded in the binary.
+var (
+	Version                = "dev"
+	Commit                 = ""
+	BuildDate              = ""
+	CelestiaAppVersion     = ""
+	HyperlaneCosmosVersion = ""
+)
+
+const (
+	celestiaAppModule     = "github.com/celestiaorg/celestia-app/v6"
+	hyperlaneCosmosModule = "github.com/bcp-innovations/hyperlane-cosmos"
+)
+
+// BuildInfo identifies the CLI build, it is recorded in deployed configs so they can be traced to the build that
+// produced them.
+type BuildInfo struct {
+	Version                string `json:"version"`
+	Commit                 string `json:"commit,omitempty"`
+	BuildDate              string `json:"build_date,omitempty"`
+	CelestiaAppVersion     string `json:"celestia_app_version,omitempty"`
+	HyperlaneCosmosVersion string `json:"hyperlane_cosmos_version,omitempty"`
+}
+
+// currentBuildInfo returns the build metadata set via -ldflags, falling back to the vcs revision and dependency
+// versions recorded by the go toolchain.
+func currentBuildInfo() *BuildInfo {
+	info := &BuildInfo{
+		Version:                Version,
+		Commit:                 Commit,
+		BuildDate:              BuildDate,
+		CelestiaAppVersion:     CelestiaAppVersion,
+		HyperlaneCosmosVersion: HyperlaneCosmosVersion,
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	for _, dep := range bi.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+
+		switch dep.Path {
+		case celestiaAppModule:
+			if info.CelestiaAppVersion == "" {
+				info.CelestiaAppVersion = dep.Version
+			}
+		case hyperlaneCosmosModule:
+			if info.HyperlaneCosmosVersion == "" {
+				info.HyperlaneCosmosVersion = dep.Version
+			}
+		}
+	}
+
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildDate == "" {
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+
+	return info
+}
+
+// String formats the build info as printed by version and --version.
+func (info *BuildInfo) String() string {
+	return fmt.Sprintf("version: %s\ncommit: %s\nbuild date: %s\ncelestia-app: %s\nhyperlane-cosmos: %s\n",
+		info.Version, orUnknown(info.Commit), orUnknown(info.BuildDate), orUnknown(info.CelestiaAppVersion), orUnknown(info.HyperlaneCosmosVersion))
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+func getVersionCmd() *cobra.Command {
+	versionCmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the CLI version, git commit, build date and dependency versions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := currentBuildInfo()
+			if output == outputJSON {
+				out, err := json.MarshalIndent(info, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal build info: %w", err)
+				}
+
+				fmt.Println(string(out))
+				return nil
+			}
+
+			fmt.Print(info)
+			return nil
+		},
+	}
+
+	return versionCmd
+}
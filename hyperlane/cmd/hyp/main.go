@@ -1,16 +1,34 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/celestiaorg/hyp-deploy/cmd/hyp/cmd"
+	"github.com/celestiaorg/hyp-deploy/pkg/broadcaster"
 )
 
 func main() {
+	// cancel pending RPCs on Ctrl-C or SIGTERM instead of blocking until they return
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	rootCmd := cmd.NewRootCmd()
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		// with --generate-only the command stops after writing the first unsigned tx
+		if errors.Is(err, broadcaster.ErrGenerateOnly) {
+			return
+		}
+
+		// consistency checks have already reported the mismatch
+		if !errors.Is(err, cmd.ErrInconsistent) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		stop()
 		os.Exit(1)
 	}
 }
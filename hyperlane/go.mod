@@ -23,6 +23,7 @@ require (
 	github.com/evstack/ev-node v1.0.0-beta.5
 	github.com/spf13/cobra v1.10.1
 	google.golang.org/grpc v1.75.0
+	sigs.k8s.io/yaml v1.4.0
 )
 
 require (
@@ -277,5 +278,4 @@ require (
 	gotest.tools/v3 v3.5.2 // indirect
 	nhooyr.io/websocket v1.8.17 // indirect
 	pgregory.net/rapid v1.2.0 // indirect
-	sigs.k8s.io/yaml v1.4.0 // indirect
 )
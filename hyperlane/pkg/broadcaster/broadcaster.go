@@ -0,0 +1,546 @@
+// Package broadcaster signs and broadcasts Cosmos SDK transactions over gRPC and waits for their confirmation.
+// It is used by the hyp CLI and can be imported by other programs to submit hyperlane messages programmatically.
+package broadcaster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/celestiaorg/celestia-app/v6/app/encoding"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cosmos/cosmos-sdk/client/grpc/cmtservice"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"google.golang.org/grpc"
+)
+
+const (
+	// mempoolFullMaxRetries and mempoolFullInitialDelay bound the retries of broadcasts rejected by a full mempool.
+	mempoolFullMaxRetries   = 5
+	mempoolFullInitialDelay = 2 * time.Second
+)
+
+// BroadcastMode selects how long BroadcastTx waits after submitting a transaction.
+type BroadcastMode int
+
+const (
+	// BroadcastModeBlock submits transactions in sync mode and waits for their confirmation.
+	BroadcastModeBlock BroadcastMode = iota
+	// BroadcastModeSync returns once the transaction passed CheckTx, without waiting for inclusion.
+	BroadcastModeSync
+	// BroadcastModeAsync returns immediately after submission, without waiting for CheckTx.
+	BroadcastModeAsync
+)
+
+// Config holds the signer and fee settings used by a Broadcaster.
+type Config struct {
+	// ChainID is the chain id transactions are signed for.
+	ChainID string
+
+	// Keyring holds the signing key named KeyName.
+	Keyring keyring.Keyring
+	KeyName string
+
+	// SignMode is the mode transactions are signed with, defaults to SIGN_MODE_DIRECT.
+	SignMode signing.SignMode
+
+	// GasLimit is a fixed gas limit per transaction. If zero, transactions are simulated and the gas used is
+	// scaled by GasAdjustment.
+	GasLimit      uint64
+	GasAdjustment float64
+
+	// FallbackGasLimit is used when simulation fails. If zero, a failed simulation fails the broadcast.
+	FallbackGasLimit uint64
+
+	// Fees is the fixed fee paid per transaction. It is ignored if GasPrices is set, in which case the fee is
+	// derived from the gas limit.
+	Fees      sdk.Coins
+	GasPrices sdk.DecCoins
+
+	// FeeGranter pays the fees of transactions from a feegrant allowance to the signer, if set.
+	FeeGranter sdk.AccAddress
+
+	// Mode selects whether BroadcastTx waits for confirmation, defaults to BroadcastModeBlock.
+	Mode BroadcastMode
+
+	// Confirmer confirms broadcast transactions in BroadcastModeBlock, defaults to polling the tx service.
+	Confirmer Confirmer
+
+	// Confirmations is the number of blocks to wait for after tx inclusion.
+	Confirmations uint64
+
+	// Retry bounds the retries of account queries and broadcasts failing at the transport level.
+	Retry RetryOptions
+
+	// GenerateOnly makes BroadcastTx write the unsigned tx to Output, which defaults to stdout, and return
+	// ErrGenerateOnly instead of signing and broadcasting it.
+	GenerateOnly bool
+	Output       io.Writer
+}
+
+// Broadcaster signs and broadcasts transactions with a single signing key.
+type Broadcaster struct {
+	enc  encoding.Config
+	conn *grpc.ClientConn
+	cfg  Config
+
+	authService authtypes.QueryClient
+	txService   txtypes.ServiceClient
+	cmtService  cmtservice.ServiceClient
+
+	address sdk.AccAddress
+
+	// mu guards the cached account and timings for concurrent broadcasts.
+	mu      sync.Mutex
+	account *authtypes.BaseAccount
+	timings []txTiming
+}
+
+// txTiming records the wall-clock duration of a single broadcast, including the confirmation wait.
+type txTiming struct {
+	msgTypes string
+	duration time.Duration
+}
+
+// New returns a Broadcaster submitting transactions over the provided gRPC connection, signed with the key
+// named in the provided config.
+func New(enc encoding.Config, conn *grpc.ClientConn, cfg Config) (*Broadcaster, error) {
+	if cfg.Keyring == nil {
+		return nil, fmt.Errorf("a keyring is required")
+	}
+
+	record, err := cfg.Keyring.Key(cfg.KeyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find key %q in keyring: %w", cfg.KeyName, err)
+	}
+
+	address, err := record.GetAddress()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get address of key %q: %w", cfg.KeyName, err)
+	}
+
+	if cfg.SignMode == signing.SignMode_SIGN_MODE_UNSPECIFIED {
+		cfg.SignMode = signing.SignMode_SIGN_MODE_DIRECT
+	}
+
+	if cfg.Output == nil {
+		cfg.Output = os.Stdout
+	}
+
+	if cfg.Confirmer == nil {
+		cfg.Confirmer = NewPollConfirmer(txtypes.NewServiceClient(conn), ConfirmOptions{})
+	}
+
+	return &Broadcaster{
+		enc:         enc,
+		conn:        conn,
+		cfg:         cfg,
+		authService: authtypes.NewQueryClient(conn),
+		txService:   txtypes.NewServiceClient(conn),
+		cmtService:  cmtservice.NewServiceClient(conn),
+		address:     address,
+	}, nil
+}
+
+// Address returns the address of the signing key.
+func (b *Broadcaster) Address() sdk.AccAddress {
+	return b.address
+}
+
+// Conn returns the gRPC connection transactions are submitted over.
+func (b *Broadcaster) Conn() *grpc.ClientConn {
+	return b.conn
+}
+
+// Encoding returns the encoding config the broadcaster was created with.
+func (b *Broadcaster) Encoding() encoding.Config {
+	return b.enc
+}
+
+// BroadcastTx signs and broadcasts the provided msgs in a single transaction and, in BroadcastModeBlock, waits
+// for its inclusion. In the other modes the submission response is returned as is and carries no events.
+// It is safe for concurrent use, the account sequence is tracked locally so that concurrent callers sign with
+// distinct sequences.
+func (b *Broadcaster) BroadcastTx(ctx context.Context, msgs ...sdk.Msg) (*sdk.TxResponse, error) {
+	if b.cfg.GenerateOnly {
+		return nil, b.writeUnsignedTx(ctx, msgs...)
+	}
+
+	start := time.Now()
+	defer b.recordTiming(start, msgs)
+
+	res, err := b.SignAndBroadcast(ctx, msgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.cfg.Mode != BroadcastModeBlock {
+		return res, nil
+	}
+
+	txResp, err := b.Confirm(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("tx confirmed", "tx_hash", txResp.TxHash, "height", txResp.Height, "code", txResp.Code, "gas_used", txResp.GasUsed)
+
+	if b.cfg.Confirmations > 0 && txResp.Height > 0 {
+		if err := b.waitForConfirmations(ctx, txResp, b.cfg.Confirmations); err != nil {
+			return nil, err
+		}
+	}
+
+	return txResp, nil
+}
+
+// Confirm waits for the tx of the provided CheckTx response to be confirmed using the configured Confirmer.
+func (b *Broadcaster) Confirm(ctx context.Context, res *sdk.TxResponse) (*sdk.TxResponse, error) {
+	return b.cfg.Confirmer.Confirm(ctx, res)
+}
+
+// SignAndBroadcast signs the provided msgs using the locally tracked account sequence and broadcasts the tx
+// in sync mode, or async mode with BroadcastModeAsync, returning the submission response without waiting for
+// inclusion. The sequence is only advanced for accepted txs, so a failed tx leaves it to the next call. On a
+// sequence mismatch the cached sequence is reset to the one expected by the node, which accounts for txs still
+// in its mempool, or dropped to be re-queried if it cannot be determined.
+func (b *Broadcaster) SignAndBroadcast(ctx context.Context, msgs ...sdk.Msg) (*sdk.TxResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.account == nil {
+		acc, err := b.queryAccount(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		b.account = acc
+	}
+
+	factory := b.factory(b.account)
+
+	gas, err := b.gasLimit(ctx, factory, msgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	txBuilder := b.enc.TxConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(msgs...); err != nil {
+		return nil, fmt.Errorf("set msgs: %w", err)
+	}
+
+	txBuilder.SetGasLimit(gas)
+	txBuilder.SetFeeAmount(b.feeAmount(gas))
+	txBuilder.SetFeeGranter(b.cfg.FeeGranter)
+
+	if err := tx.Sign(ctx, factory, b.cfg.KeyName, txBuilder, false); err != nil {
+		return nil, fmt.Errorf("failed to sign tx: %w", err)
+	}
+
+	txBytes, err := b.enc.TxConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return nil, fmt.Errorf("encode tx: %w", err)
+	}
+
+	broadcastTxReq := &txtypes.BroadcastTxRequest{
+		Mode:    txtypes.BroadcastMode_BROADCAST_MODE_SYNC,
+		TxBytes: txBytes,
+	}
+
+	if b.cfg.Mode == BroadcastModeAsync {
+		broadcastTxReq.Mode = txtypes.BroadcastMode_BROADCAST_MODE_ASYNC
+	}
+
+	var attempts int
+	res, err := withRetry(ctx, b.conn, b.cfg.Retry, "BroadcastTx", func() (*txtypes.BroadcastTxResponse, error) {
+		attempts++
+		return broadcastWithMempoolRetry(ctx, b.txService, broadcastTxReq)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// a retried broadcast is rejected as a duplicate if the failed attempt reached the node
+	if attempts > 1 && isTxInMempoolCache(res.TxResponse) {
+		slog.Info("tx was received before the transport failure", "tx_hash", res.TxResponse.TxHash)
+		res.TxResponse.Code = abci.CodeTypeOK
+	}
+
+	if res.TxResponse.Code != abci.CodeTypeOK {
+		if isSequenceMismatch(res.TxResponse) {
+			b.resetSequence(res.TxResponse)
+		}
+		return nil, fmt.Errorf("failed response: %v", res.TxResponse)
+	}
+
+	slog.Debug("tx submitted", "tx_hash", res.TxResponse.TxHash, "sequence", b.account.Sequence, "gas_limit", gas)
+
+	b.account.Sequence++
+
+	return res.TxResponse, nil
+}
+
+// resetSequence resets the cached sequence after the provided sequence mismatch response. It must be called with
+// the mutex held.
+func (b *Broadcaster) resetSequence(res *sdk.TxResponse) {
+	expected, ok := expectedSequence(res.RawLog)
+	if !ok {
+		slog.Warn("account sequence mismatch, re-querying the account", "sequence", b.account.Sequence)
+		b.account = nil
+		return
+	}
+
+	slog.Warn("account sequence mismatch, resetting the sequence", "sequence", b.account.Sequence, "expected", expected)
+	b.account.Sequence = expected
+}
+
+func isSequenceMismatch(res *sdk.TxResponse) bool {
+	return res.Codespace == sdkerrors.ErrWrongSequence.Codespace() && res.Code == sdkerrors.ErrWrongSequence.ABCICode()
+}
+
+// expectedSequence parses the sequence expected by the node from the log of a sequence mismatch response, written
+// by the sdk ante handler as "account sequence mismatch, expected 12, got 10".
+func expectedSequence(rawLog string) (uint64, bool) {
+	var expected, got uint64
+	i := strings.Index(rawLog, "account sequence mismatch")
+	if i < 0 {
+		return 0, false
+	}
+
+	if _, err := fmt.Sscanf(rawLog[i:], "account sequence mismatch, expected %d, got %d", &expected, &got); err != nil {
+		return 0, false
+	}
+
+	return expected, true
+}
+
+// factory returns the tx factory signing with the configured key for the provided account.
+func (b *Broadcaster) factory(acc *authtypes.BaseAccount) tx.Factory {
+	return tx.Factory{}.
+		WithKeybase(b.cfg.Keyring).
+		WithFromName(b.cfg.KeyName).
+		WithSignMode(b.cfg.SignMode).
+		WithTxConfig(b.enc.TxConfig).
+		WithChainID(b.cfg.ChainID).
+		WithAccountNumber(acc.AccountNumber).
+		WithSequence(acc.Sequence).
+		WithFeeGranter(b.cfg.FeeGranter)
+}
+
+// gasLimit returns the gas limit for a tx containing the provided msgs. A fixed gas limit is used as is,
+// otherwise the tx is simulated and the gas used is scaled by the gas adjustment. If simulation fails the
+// fallback gas limit is used, if any.
+func (b *Broadcaster) gasLimit(ctx context.Context, factory tx.Factory, msgs ...sdk.Msg) (uint64, error) {
+	if b.cfg.GasLimit > 0 {
+		return b.cfg.GasLimit, nil
+	}
+
+	gasUsed, err := b.simulate(ctx, factory, msgs...)
+	if err != nil {
+		if b.cfg.FallbackGasLimit == 0 {
+			return 0, fmt.Errorf("failed to simulate tx: %w", err)
+		}
+
+		slog.Warn("failed to simulate tx, using fallback gas limit", "gas_limit", b.cfg.FallbackGasLimit, "err", err)
+		return b.cfg.FallbackGasLimit, nil
+	}
+
+	return uint64(float64(gasUsed) * b.cfg.GasAdjustment), nil
+}
+
+// EstimatedFees returns the fees paid for the provided number of transactions. With gas prices, the fee of each
+// transaction is derived from the fixed gas limit, or the fallback gas limit as an estimate for simulated ones.
+func (b *Broadcaster) EstimatedFees(txs int) sdk.Coins {
+	gas := b.cfg.GasLimit
+	if gas == 0 {
+		gas = b.cfg.FallbackGasLimit
+	}
+
+	return b.feeAmount(gas).MulInt(math.NewInt(int64(txs)))
+}
+
+// feeAmount returns the tx fee for the provided gas limit. Gas prices are multiplied by the gas limit and
+// rounded up, otherwise the fixed fee is used.
+func (b *Broadcaster) feeAmount(gas uint64) sdk.Coins {
+	if b.cfg.GasPrices.Empty() {
+		return b.cfg.Fees
+	}
+
+	gasDec := math.LegacyNewDecFromInt(math.NewIntFromUint64(gas))
+	coins := make(sdk.Coins, len(b.cfg.GasPrices))
+	for i, price := range b.cfg.GasPrices {
+		coins[i] = sdk.NewCoin(price.Denom, price.Amount.Mul(gasDec).Ceil().RoundInt())
+	}
+
+	return coins.Sort()
+}
+
+// simulate runs the provided msgs through the tx service simulation and returns the gas used.
+func (b *Broadcaster) simulate(ctx context.Context, factory tx.Factory, msgs ...sdk.Msg) (uint64, error) {
+	simTxBytes, err := factory.WithSimulateAndExecute(true).BuildSimTx(msgs...)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := b.txService.Simulate(ctx, &txtypes.SimulateRequest{TxBytes: simTxBytes})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.GasInfo.GasUsed, nil
+}
+
+// broadcastWithMempoolRetry broadcasts the provided request, retrying with exponential backoff while the node
+// reports that its mempool is full. Any other response is returned as is.
+func broadcastWithMempoolRetry(ctx context.Context, txService txtypes.ServiceClient, req *txtypes.BroadcastTxRequest) (*txtypes.BroadcastTxResponse, error) {
+	delay := mempoolFullInitialDelay
+	for attempt := 0; ; attempt++ {
+		res, err := txService.BroadcastTx(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isMempoolFull(res.TxResponse) || attempt == mempoolFullMaxRetries {
+			return res, nil
+		}
+
+		slog.Warn("mempool is full, retrying", "delay", delay, "attempt", attempt+1, "max_attempts", mempoolFullMaxRetries)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+func isMempoolFull(res *sdk.TxResponse) bool {
+	return res.Codespace == sdkerrors.ErrMempoolIsFull.Codespace() && res.Code == sdkerrors.ErrMempoolIsFull.ABCICode()
+}
+
+// queryAccount queries the account of the signer, retrying transport failures.
+func (b *Broadcaster) queryAccount(ctx context.Context) (*authtypes.BaseAccount, error) {
+	return withRetry(ctx, b.conn, b.cfg.Retry, "Account", func() (*authtypes.BaseAccount, error) {
+		return QueryAccount(ctx, b.enc, b.authService, b.address.String())
+	})
+}
+
+// QueryAccount queries the base account with the provided address, returning its account number and sequence.
+func QueryAccount(ctx context.Context, enc encoding.Config, authService authtypes.QueryClient, address string) (*authtypes.BaseAccount, error) {
+	accRes, err := authService.Account(ctx, &authtypes.QueryAccountRequest{Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account: %w", err)
+	}
+
+	var acc authtypes.BaseAccount
+	if err := enc.Codec.Unmarshal(accRes.Account.Value, &acc); err != nil {
+		return nil, fmt.Errorf("unmarshal account: %w", err)
+	}
+
+	return &acc, nil
+}
+
+// BroadcastTxBatches splits the provided msgs into transactions of at most batchSize msgs each and broadcasts
+// them in order. A batchSize of zero or less broadcasts all msgs in a single transaction.
+func (b *Broadcaster) BroadcastTxBatches(ctx context.Context, batchSize int, msgs ...sdk.Msg) ([]*sdk.TxResponse, error) {
+	if batchSize <= 0 || batchSize > len(msgs) {
+		batchSize = len(msgs)
+	}
+
+	var responses []*sdk.TxResponse
+	for start := 0; start < len(msgs); start += batchSize {
+		end := min(start+batchSize, len(msgs))
+
+		res, err := b.BroadcastTx(ctx, msgs[start:end]...)
+		if err != nil {
+			return responses, fmt.Errorf("broadcast batch of %d msgs: %w", end-start, err)
+		}
+		slog.Info("broadcast batch", "msgs", end-start, "tx_hash", res.TxHash)
+
+		responses = append(responses, res)
+	}
+
+	return responses, nil
+}
+
+// PrintTimings prints the wall-clock time spent on each broadcast so far and the total.
+func (b *Broadcaster) PrintTimings() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var total time.Duration
+	for _, t := range b.timings {
+		fmt.Printf("%-80s %s\n", t.msgTypes, t.duration.Round(time.Millisecond))
+		total += t.duration
+	}
+
+	fmt.Printf("%-80s %s\n", "total", total.Round(time.Millisecond))
+}
+
+func (b *Broadcaster) recordTiming(start time.Time, msgs []sdk.Msg) {
+	msgTypes := make([]string, len(msgs))
+	for i, msg := range msgs {
+		msgTypes[i] = sdk.MsgTypeURL(msg)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.timings = append(b.timings, txTiming{
+		msgTypes: strings.Join(msgTypes, ","),
+		duration: time.Since(start),
+	})
+}
+
+// waitForConfirmations blocks until the chain reaches n blocks past the inclusion height of the provided tx,
+// verifying the tx is still present once the target height is reached.
+func (b *Broadcaster) waitForConfirmations(ctx context.Context, txResp *sdk.TxResponse, n uint64) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(n+5)*6*time.Second)
+	defer cancel()
+
+	ticker := time.NewTicker(6 * time.Second)
+	defer ticker.Stop()
+
+	targetHeight := txResp.Height + int64(n)
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout exceeded while waiting for %d confirmations: %w", n, ctx.Err())
+		case <-ticker.C:
+			res, err := b.cmtService.GetLatestBlock(ctx, &cmtservice.GetLatestBlockRequest{})
+			if err != nil {
+				// Treat as retryable
+				continue
+			}
+
+			if res.SdkBlock == nil || res.SdkBlock.Header.Height < targetHeight {
+				continue
+			}
+
+			txRes, err := b.txService.GetTx(ctx, &txtypes.GetTxRequest{Hash: txResp.TxHash})
+			if err != nil {
+				return fmt.Errorf("tx %s no longer found after %d confirmations: %w", txResp.TxHash, n, err)
+			}
+
+			if txRes.TxResponse.Height != txResp.Height {
+				return fmt.Errorf("tx %s moved from height %d to %d", txResp.TxHash, txResp.Height, txRes.TxResponse.Height)
+			}
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package broadcaster
+
+import (
+	"testing"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func TestExpectedSequence(t *testing.T) {
+	tests := []struct {
+		name   string
+		rawLog string
+		want   uint64
+		wantOk bool
+	}{
+		{
+			name:   "ante handler log",
+			rawLog: "account sequence mismatch, expected 12, got 10: incorrect account sequence",
+			want:   12,
+			wantOk: true,
+		},
+		{
+			name:   "wrapped log",
+			rawLog: "failed to execute message; message index: 0: account sequence mismatch, expected 3, got 7",
+			want:   3,
+			wantOk: true,
+		},
+		{
+			name:   "other error",
+			rawLog: "insufficient fees; got: 10utia required: 800utia",
+		},
+		{
+			name:   "missing expected sequence",
+			rawLog: "account sequence mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := expectedSequence(tt.rawLog)
+			if ok != tt.wantOk || got != tt.want {
+				t.Fatalf("got (%d, %t), want (%d, %t)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestIsSequenceMismatch(t *testing.T) {
+	tests := []struct {
+		name string
+		res  *sdk.TxResponse
+		want bool
+	}{
+		{
+			name: "wrong sequence",
+			res:  &sdk.TxResponse{Codespace: sdkerrors.ErrWrongSequence.Codespace(), Code: sdkerrors.ErrWrongSequence.ABCICode()},
+			want: true,
+		},
+		{
+			name: "insufficient fee",
+			res:  &sdk.TxResponse{Codespace: sdkerrors.ErrInsufficientFee.Codespace(), Code: sdkerrors.ErrInsufficientFee.ABCICode()},
+		},
+		{
+			name: "same code in another codespace",
+			res:  &sdk.TxResponse{Codespace: "hyperlane", Code: sdkerrors.ErrWrongSequence.ABCICode()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSequenceMismatch(tt.res); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeeAmount(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		gas  uint64
+		want sdk.Coins
+	}{
+		{
+			name: "fixed fees",
+			cfg:  Config{Fees: sdk.NewCoins(sdk.NewInt64Coin("utia", 800))},
+			gas:  200000,
+			want: sdk.NewCoins(sdk.NewInt64Coin("utia", 800)),
+		},
+		{
+			name: "gas prices",
+			cfg:  Config{GasPrices: sdk.NewDecCoins(sdk.NewDecCoinFromDec("utia", math.LegacyMustNewDecFromStr("0.025")))},
+			gas:  200000,
+			want: sdk.NewCoins(sdk.NewInt64Coin("utia", 5000)),
+		},
+		{
+			name: "gas prices rounded up",
+			cfg:  Config{GasPrices: sdk.NewDecCoins(sdk.NewDecCoinFromDec("utia", math.LegacyMustNewDecFromStr("0.025")))},
+			gas:  200001,
+			want: sdk.NewCoins(sdk.NewInt64Coin("utia", 5001)),
+		},
+		{
+			name: "gas prices take precedence over fixed fees",
+			cfg: Config{
+				Fees:      sdk.NewCoins(sdk.NewInt64Coin("utia", 800)),
+				GasPrices: sdk.NewDecCoins(sdk.NewDecCoinFromDec("utia", math.LegacyMustNewDecFromStr("0.002"))),
+			},
+			gas:  100000,
+			want: sdk.NewCoins(sdk.NewInt64Coin("utia", 200)),
+		},
+		{
+			name: "multiple gas prices",
+			cfg: Config{GasPrices: sdk.NewDecCoins(
+				sdk.NewDecCoinFromDec("utia", math.LegacyMustNewDecFromStr("0.01")),
+				sdk.NewDecCoinFromDec("stake", math.LegacyMustNewDecFromStr("0.5")),
+			)},
+			gas:  1000,
+			want: sdk.NewCoins(sdk.NewInt64Coin("stake", 500), sdk.NewInt64Coin("utia", 10)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Broadcaster{cfg: tt.cfg}
+			if got := b.feeAmount(tt.gas); !got.Equal(tt.want) {
+				t.Fatalf("got fee %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimatedFees(t *testing.T) {
+	gasPrices := sdk.NewDecCoins(sdk.NewDecCoinFromDec("utia", math.LegacyMustNewDecFromStr("0.025")))
+
+	tests := []struct {
+		name string
+		cfg  Config
+		txs  int
+		want sdk.Coins
+	}{
+		{
+			name: "fixed fees",
+			cfg:  Config{Fees: sdk.NewCoins(sdk.NewInt64Coin("utia", 800))},
+			txs:  6,
+			want: sdk.NewCoins(sdk.NewInt64Coin("utia", 4800)),
+		},
+		{
+			name: "gas prices with fixed gas limit",
+			cfg:  Config{GasPrices: gasPrices, GasLimit: 100000, FallbackGasLimit: 400000},
+			txs:  3,
+			want: sdk.NewCoins(sdk.NewInt64Coin("utia", 7500)),
+		},
+		{
+			name: "gas prices with simulated gas",
+			cfg:  Config{GasPrices: gasPrices, FallbackGasLimit: 400000},
+			txs:  3,
+			want: sdk.NewCoins(sdk.NewInt64Coin("utia", 30000)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Broadcaster{cfg: tt.cfg}
+			if got := b.EstimatedFees(tt.txs); !got.Equal(tt.want) {
+				t.Fatalf("got fees %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
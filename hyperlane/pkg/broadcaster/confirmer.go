@@ -0,0 +1,174 @@
+package broadcaster
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	rpcclient "github.com/cometbft/cometbft/rpc/client/http"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultConfirmTimeout, DefaultConfirmInterval and DefaultConfirmMaxInterval are used for zero ConfirmOptions.
+	DefaultConfirmTimeout     = 30 * time.Second
+	DefaultConfirmInterval    = time.Second
+	DefaultConfirmMaxInterval = 10 * time.Second
+)
+
+// ConfirmOptions bound how long confirmers wait for a transaction. Zero values use the defaults.
+type ConfirmOptions struct {
+	// Timeout is the overall deadline for a transaction to be confirmed.
+	Timeout time.Duration
+
+	// Interval is the initial delay between polls, doubled after every poll up to MaxInterval.
+	Interval    time.Duration
+	MaxInterval time.Duration
+}
+
+func (o ConfirmOptions) withDefaults() ConfirmOptions {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultConfirmTimeout
+	}
+
+	if o.Interval <= 0 {
+		o.Interval = DefaultConfirmInterval
+	}
+
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = DefaultConfirmMaxInterval
+	}
+
+	o.MaxInterval = max(o.MaxInterval, o.Interval)
+	return o
+}
+
+// Confirmer waits for a broadcast transaction to be confirmed.
+type Confirmer interface {
+	// Confirm blocks until the tx of the provided CheckTx response is confirmed and returns the final tx response.
+	Confirm(ctx context.Context, res *sdk.TxResponse) (*sdk.TxResponse, error)
+}
+
+// NewPollConfirmer returns a Confirmer that queries the tx service by hash with exponential backoff until the tx
+// is included.
+func NewPollConfirmer(txService txtypes.ServiceClient, opts ConfirmOptions) Confirmer {
+	return &pollConfirmer{txService: txService, opts: opts.withDefaults()}
+}
+
+// NewEventConfirmer returns a Confirmer that subscribes to the tx event over the CometBFT websocket at rpcAddr.
+// Only the timeout of the provided options is used.
+func NewEventConfirmer(rpcAddr string, txService txtypes.ServiceClient, opts ConfirmOptions) Confirmer {
+	return &eventConfirmer{rpcAddr: rpcAddr, txService: txService, opts: opts.withDefaults()}
+}
+
+// NewAsyncConfirmer returns a Confirmer that does not wait for inclusion and returns the CheckTx response as is.
+func NewAsyncConfirmer() Confirmer {
+	return asyncConfirmer{}
+}
+
+// pollConfirmer confirms transactions by querying the tx service by hash with exponential backoff.
+type pollConfirmer struct {
+	txService txtypes.ServiceClient
+	opts      ConfirmOptions
+}
+
+func (c *pollConfirmer) Confirm(ctx context.Context, txResp *sdk.TxResponse) (*sdk.TxResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	interval := c.opts.Interval
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timeout exceeded while waiting for tx %s confirmation: %w", txResp.TxHash, ctx.Err())
+		case <-time.After(withJitter(interval)):
+		}
+
+		interval = min(2*interval, c.opts.MaxInterval)
+
+		res, err := c.txService.GetTx(ctx, &txtypes.GetTxRequest{Hash: txResp.TxHash})
+		if err != nil {
+			// the tx is not indexed until it is included, any other error is treated as transient
+			if status.Code(err) != codes.NotFound {
+				slog.Debug("failed to query tx, retrying", "tx_hash", txResp.TxHash, "err", err)
+			}
+			continue
+		}
+
+		if res != nil && res.TxResponse.Height > 0 {
+			return checkTxResult(res.TxResponse)
+		}
+	}
+}
+
+// withJitter returns the provided interval extended by up to 10% so that concurrent pollers spread out.
+func withJitter(interval time.Duration) time.Duration {
+	return interval + rand.N(interval/10+1)
+}
+
+// checkTxResult returns the provided included tx response, or an error if the tx failed execution.
+func checkTxResult(res *sdk.TxResponse) (*sdk.TxResponse, error) {
+	if res.Code != abci.CodeTypeOK {
+		return nil, fmt.Errorf("tx %s failed at height %d with code %d (%s): %s", res.TxHash, res.Height, res.Code, res.Codespace, res.RawLog)
+	}
+
+	return res, nil
+}
+
+// eventConfirmer confirms transactions by subscribing to their tx event over the CometBFT websocket.
+type eventConfirmer struct {
+	rpcAddr   string
+	txService txtypes.ServiceClient
+	opts      ConfirmOptions
+}
+
+func (c *eventConfirmer) Confirm(ctx context.Context, res *sdk.TxResponse) (*sdk.TxResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	client, err := rpcclient.New(c.rpcAddr, "/websocket")
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to CometBFT RPC: %w", err)
+	}
+
+	if err := client.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start websocket client: %w", err)
+	}
+	defer client.Stop()
+
+	query := fmt.Sprintf("tm.event='Tx' AND tx.hash='%s'", res.TxHash)
+	events, err := client.Subscribe(ctx, "hyp", query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to tx event: %w", err)
+	}
+
+	// the tx may have been included before the subscription was established, in which case its event was missed
+	if txRes, err := c.txService.GetTx(ctx, &txtypes.GetTxRequest{Hash: res.TxHash}); err == nil && txRes.TxResponse != nil && txRes.TxResponse.Height > 0 {
+		return checkTxResult(txRes.TxResponse)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timeout exceeded while waiting for tx event: %w", ctx.Err())
+	case <-events:
+		txRes, err := c.txService.GetTx(ctx, &txtypes.GetTxRequest{Hash: res.TxHash})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tx after event: %w", err)
+		}
+
+		return checkTxResult(txRes.TxResponse)
+	}
+}
+
+// asyncConfirmer does not wait for inclusion and returns the CheckTx response as is.
+type asyncConfirmer struct{}
+
+func (asyncConfirmer) Confirm(_ context.Context, res *sdk.TxResponse) (*sdk.TxResponse, error) {
+	return res, nil
+}
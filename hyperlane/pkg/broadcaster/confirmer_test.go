@@ -0,0 +1,165 @@
+package broadcaster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeTxService is a txtypes.ServiceClient answering GetTx queries from a list of responses, returning the last
+// one once the list is exhausted, and broadcasts with the broadcast func. Calling any other method panics.
+type fakeTxService struct {
+	txtypes.ServiceClient
+
+	responses []fakeGetTx
+	calls     int
+
+	broadcast func(req *txtypes.BroadcastTxRequest) (*txtypes.BroadcastTxResponse, error)
+}
+
+type fakeGetTx struct {
+	res *txtypes.GetTxResponse
+	err error
+}
+
+func (s *fakeTxService) GetTx(_ context.Context, _ *txtypes.GetTxRequest, _ ...grpc.CallOption) (*txtypes.GetTxResponse, error) {
+	r := s.responses[min(s.calls, len(s.responses)-1)]
+	s.calls++
+	return r.res, r.err
+}
+
+func (s *fakeTxService) BroadcastTx(_ context.Context, req *txtypes.BroadcastTxRequest, _ ...grpc.CallOption) (*txtypes.BroadcastTxResponse, error) {
+	return s.broadcast(req)
+}
+
+func included(height int64, code uint32) fakeGetTx {
+	return fakeGetTx{res: &txtypes.GetTxResponse{TxResponse: &sdk.TxResponse{TxHash: "ABCD", Height: height, Code: code}}}
+}
+
+func notFound() fakeGetTx {
+	return fakeGetTx{err: status.Error(codes.NotFound, "tx not found")}
+}
+
+func TestPollConfirmer(t *testing.T) {
+	opts := ConfirmOptions{Timeout: 100 * time.Millisecond, Interval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+	tests := []struct {
+		name      string
+		responses []fakeGetTx
+		wantErr   bool
+	}{
+		{
+			name:      "included after not found",
+			responses: []fakeGetTx{notFound(), notFound(), included(10, 0)},
+		},
+		{
+			name:      "included after transient error",
+			responses: []fakeGetTx{{err: status.Error(codes.Unavailable, "unavailable")}, included(10, 0)},
+		},
+		{
+			name:      "failed execution",
+			responses: []fakeGetTx{notFound(), included(10, 5)},
+			wantErr:   true,
+		},
+		{
+			name:      "timeout",
+			responses: []fakeGetTx{notFound()},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txService := &fakeTxService{responses: tt.responses}
+			confirmer := NewPollConfirmer(txService, opts)
+
+			res, err := confirmer.Confirm(context.Background(), &sdk.TxResponse{TxHash: "ABCD"})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got response at height %d", res.Height)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if res.Height != 10 {
+				t.Fatalf("got height %d, want 10", res.Height)
+			}
+
+			if txService.calls != len(tt.responses) {
+				t.Fatalf("got %d queries, want %d", txService.calls, len(tt.responses))
+			}
+		})
+	}
+}
+
+func TestPollConfirmerTimeout(t *testing.T) {
+	opts := ConfirmOptions{Timeout: 20 * time.Millisecond, Interval: time.Millisecond}
+	confirmer := NewPollConfirmer(&fakeTxService{responses: []fakeGetTx{notFound()}}, opts)
+
+	_, err := confirmer.Confirm(context.Background(), &sdk.TxResponse{TxHash: "ABCD"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestCheckTxResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		res     *sdk.TxResponse
+		wantErr bool
+	}{
+		{
+			name: "success",
+			res:  &sdk.TxResponse{TxHash: "ABCD", Height: 10},
+		},
+		{
+			name:    "failed execution",
+			res:     &sdk.TxResponse{TxHash: "ABCD", Height: 10, Code: 11, Codespace: "sdk", RawLog: "out of gas"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := checkTxResult(tt.res)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if res != tt.res {
+				t.Fatal("expected the provided response to be returned")
+			}
+		})
+	}
+}
+
+func TestAsyncConfirmer(t *testing.T) {
+	checkTx := &sdk.TxResponse{TxHash: "ABCD"}
+
+	res, err := NewAsyncConfirmer().Confirm(context.Background(), checkTx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res != checkTx {
+		t.Fatal("expected the CheckTx response to be returned as is")
+	}
+}
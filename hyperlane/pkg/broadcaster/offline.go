@@ -0,0 +1,139 @@
+package broadcaster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/celestiaorg/celestia-app/v6/app/encoding"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// ErrGenerateOnly is returned by BroadcastTx in generate-only mode after the unsigned tx has been written.
+var ErrGenerateOnly = errors.New("unsigned tx generated, not broadcasting")
+
+// UnsignedTx is an unsigned transaction along with the signer data needed to sign it offline.
+type UnsignedTx struct {
+	ChainID       string          `json:"chain_id"`
+	AccountNumber uint64          `json:"account_number,string"`
+	Sequence      uint64          `json:"sequence,string"`
+	Tx            json.RawMessage `json:"tx"`
+}
+
+// GenerateTx builds an unsigned tx for the provided msgs with the gas limit and fee the broadcaster would use,
+// along with the current account number and sequence of the signer.
+func (b *Broadcaster) GenerateTx(ctx context.Context, msgs ...sdk.Msg) (*UnsignedTx, error) {
+	acc, err := b.queryAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	factory := b.factory(acc)
+
+	gas, err := b.gasLimit(ctx, factory, msgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	txBuilder := b.enc.TxConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(msgs...); err != nil {
+		return nil, fmt.Errorf("set msgs: %w", err)
+	}
+
+	txBuilder.SetGasLimit(gas)
+	txBuilder.SetFeeAmount(b.feeAmount(gas))
+	txBuilder.SetFeeGranter(b.cfg.FeeGranter)
+
+	txJSON, err := b.enc.TxConfig.TxJSONEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return nil, fmt.Errorf("encode tx: %w", err)
+	}
+
+	return &UnsignedTx{
+		ChainID:       b.cfg.ChainID,
+		AccountNumber: acc.AccountNumber,
+		Sequence:      acc.Sequence,
+		Tx:            txJSON,
+	}, nil
+}
+
+// writeUnsignedTx generates the unsigned tx for the provided msgs and writes it to the configured output.
+func (b *Broadcaster) writeUnsignedTx(ctx context.Context, msgs ...sdk.Msg) error {
+	unsignedTx, err := b.GenerateTx(ctx, msgs...)
+	if err != nil {
+		return err
+	}
+
+	bz, err := json.MarshalIndent(unsignedTx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal unsigned tx: %w", err)
+	}
+
+	if _, err := b.cfg.Output.Write(append(bz, '\n')); err != nil {
+		return fmt.Errorf("failed to write unsigned tx: %w", err)
+	}
+
+	return ErrGenerateOnly
+}
+
+// SignTx signs the provided unsigned tx with the key named keyName in the keyring. It does not require a
+// connection to the chain, the signer data is taken from the unsigned tx.
+func SignTx(ctx context.Context, enc encoding.Config, kr keyring.Keyring, keyName string, signMode signing.SignMode, unsignedTx *UnsignedTx) (sdk.Tx, error) {
+	stdTx, err := enc.TxConfig.TxJSONDecoder()(unsignedTx.Tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode tx: %w", err)
+	}
+
+	txBuilder, err := enc.TxConfig.WrapTxBuilder(stdTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap tx: %w", err)
+	}
+
+	if signMode == signing.SignMode_SIGN_MODE_UNSPECIFIED {
+		signMode = signing.SignMode_SIGN_MODE_DIRECT
+	}
+
+	factory := tx.Factory{}.
+		WithKeybase(kr).
+		WithFromName(keyName).
+		WithSignMode(signMode).
+		WithTxConfig(enc.TxConfig).
+		WithChainID(unsignedTx.ChainID).
+		WithAccountNumber(unsignedTx.AccountNumber).
+		WithSequence(unsignedTx.Sequence)
+
+	if err := tx.Sign(ctx, factory, keyName, txBuilder, true); err != nil {
+		return nil, fmt.Errorf("failed to sign tx: %w", err)
+	}
+
+	return txBuilder.GetTx(), nil
+}
+
+// BroadcastSignedTx broadcasts an already signed tx in sync mode and waits for its confirmation using the provided
+// Confirmer, returning the confirmed tx response.
+func BroadcastSignedTx(ctx context.Context, enc encoding.Config, txService txtypes.ServiceClient, confirmer Confirmer, signedTx sdk.Tx) (*sdk.TxResponse, error) {
+	txBytes, err := enc.TxConfig.TxEncoder()(signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("encode tx: %w", err)
+	}
+
+	res, err := broadcastWithMempoolRetry(ctx, txService, &txtypes.BroadcastTxRequest{
+		Mode:    txtypes.BroadcastMode_BROADCAST_MODE_SYNC,
+		TxBytes: txBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.TxResponse.Code != abci.CodeTypeOK {
+		return nil, fmt.Errorf("failed response: %v", res.TxResponse)
+	}
+
+	return confirmer.Confirm(ctx, res.TxResponse)
+}
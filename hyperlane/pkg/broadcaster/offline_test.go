@@ -0,0 +1,155 @@
+package broadcaster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/celestiaorg/celestia-app/v6/app"
+	"github.com/celestiaorg/celestia-app/v6/app/encoding"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+)
+
+// fakeAuthService is an authtypes.QueryClient returning the provided account. Calling any other method panics.
+type fakeAuthService struct {
+	authtypes.QueryClient
+
+	account *authtypes.BaseAccount
+}
+
+func (s *fakeAuthService) Account(_ context.Context, _ *authtypes.QueryAccountRequest, _ ...grpc.CallOption) (*authtypes.QueryAccountResponse, error) {
+	acc, err := codectypes.NewAnyWithValue(s.account)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authtypes.QueryAccountResponse{Account: acc}, nil
+}
+
+func TestGenerateSignBroadcastTx(t *testing.T) {
+	ctx := context.Background()
+	enc := encoding.MakeConfig(app.ModuleEncodingRegisters...)
+
+	kr := keyring.NewInMemory(enc.Codec)
+	record, _, err := kr.NewMnemonic("signer", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+
+	signer, err := record.GetAddress()
+	if err != nil {
+		t.Fatalf("failed to get address: %v", err)
+	}
+
+	const accountNumber, sequence = 7, 3
+	fees := sdk.NewCoins(sdk.NewInt64Coin("utia", 800))
+
+	b := &Broadcaster{
+		enc: enc,
+		cfg: Config{
+			ChainID:  "test-chain",
+			Keyring:  kr,
+			KeyName:  "signer",
+			SignMode: signing.SignMode_SIGN_MODE_DIRECT,
+			GasLimit: 100000,
+			Fees:     fees,
+		},
+		authService: &fakeAuthService{account: &authtypes.BaseAccount{Address: signer.String(), AccountNumber: accountNumber, Sequence: sequence}},
+		address:     signer,
+	}
+
+	msg := &banktypes.MsgSend{FromAddress: signer.String(), ToAddress: signer.String(), Amount: sdk.NewCoins(sdk.NewInt64Coin("utia", 1))}
+
+	unsignedTx, err := b.GenerateTx(ctx, msg)
+	if err != nil {
+		t.Fatalf("failed to generate tx: %v", err)
+	}
+
+	// the unsigned tx is carried to the offline machine as JSON
+	bz, err := json.Marshal(unsignedTx)
+	if err != nil {
+		t.Fatalf("failed to marshal unsigned tx: %v", err)
+	}
+
+	var decoded UnsignedTx
+	if err := json.Unmarshal(bz, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal unsigned tx: %v", err)
+	}
+
+	if decoded.ChainID != "test-chain" || decoded.AccountNumber != accountNumber || decoded.Sequence != sequence {
+		t.Fatalf("got signer data %s/%d/%d, want test-chain/%d/%d", decoded.ChainID, decoded.AccountNumber, decoded.Sequence, accountNumber, sequence)
+	}
+
+	signedTx, err := SignTx(ctx, enc, kr, "signer", signing.SignMode_SIGN_MODE_DIRECT, &decoded)
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+
+	var broadcast []byte
+	txService := &fakeTxService{
+		broadcast: func(req *txtypes.BroadcastTxRequest) (*txtypes.BroadcastTxResponse, error) {
+			broadcast = req.TxBytes
+			return &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: "ABCD"}}, nil
+		},
+	}
+
+	res, err := BroadcastSignedTx(ctx, enc, txService, NewAsyncConfirmer(), signedTx)
+	if err != nil {
+		t.Fatalf("failed to broadcast tx: %v", err)
+	}
+
+	if res.TxHash != "ABCD" {
+		t.Fatalf("got tx hash %s, want ABCD", res.TxHash)
+	}
+
+	tx, err := enc.TxConfig.TxDecoder()(broadcast)
+	if err != nil {
+		t.Fatalf("failed to decode broadcast tx: %v", err)
+	}
+
+	msgs := tx.GetMsgs()
+	if len(msgs) != 1 {
+		t.Fatalf("got %d msgs, want 1", len(msgs))
+	}
+
+	if got, ok := msgs[0].(*banktypes.MsgSend); !ok || !got.Amount.Equal(msg.Amount) {
+		t.Fatalf("got msg %v, want %v", msgs[0], msg)
+	}
+
+	feeTx, ok := tx.(sdk.FeeTx)
+	if !ok {
+		t.Fatalf("broadcast tx %T has no fee", tx)
+	}
+
+	if feeTx.GetGas() != 100000 || !feeTx.GetFee().Equal(fees) {
+		t.Fatalf("got gas %d and fee %s, want 100000 and %s", feeTx.GetGas(), feeTx.GetFee(), fees)
+	}
+
+	sigTx, ok := tx.(authsigning.SigVerifiableTx)
+	if !ok {
+		t.Fatalf("broadcast tx %T has no signatures", tx)
+	}
+
+	sigs, err := sigTx.GetSignaturesV2()
+	if err != nil {
+		t.Fatalf("failed to get signatures: %v", err)
+	}
+
+	if len(sigs) != 1 || sigs[0].Sequence != sequence {
+		t.Fatalf("got signatures %v, want one with sequence %d", sigs, sequence)
+	}
+
+	if !bytes.Equal(sigs[0].PubKey.Address(), signer) {
+		t.Fatalf("tx signed by %s, want %s", sdk.AccAddress(sigs[0].PubKey.Address()), signer)
+	}
+}
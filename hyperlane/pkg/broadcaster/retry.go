@@ -0,0 +1,67 @@
+package broadcaster
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultRetryDelay is used for a zero RetryOptions.Delay.
+const DefaultRetryDelay = time.Second
+
+// RetryOptions bound the retries of gRPC calls that fail at the transport level. Calls rejected by the node, such
+// as a tx returning a non-zero code, are never retried.
+type RetryOptions struct {
+	// Retries is the number of times a call is retried after its first attempt, zero disables retries.
+	Retries int
+
+	// Delay is the wait before each retry.
+	Delay time.Duration
+}
+
+// withRetry calls fn until it succeeds or fails with an error other than a transport failure, at most
+// opts.Retries more times. Before each retry the connection is asked to reconnect, as a connection that lost its
+// transport otherwise waits out its reconnect backoff.
+func withRetry[T any](ctx context.Context, conn *grpc.ClientConn, opts RetryOptions, method string, fn func() (T, error)) (T, error) {
+	delay := opts.Delay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+
+	for attempt := 0; ; attempt++ {
+		res, err := fn()
+		if err == nil || !isTransportError(err) || attempt == opts.Retries {
+			return res, err
+		}
+
+		slog.Warn("gRPC call failed, reconnecting", "method", method, "delay", delay, "attempt", attempt+1, "max_attempts", opts.Retries, "err", err)
+
+		select {
+		case <-ctx.Done():
+			return res, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		if conn != nil {
+			conn.Connect()
+		}
+	}
+}
+
+// isTransportError reports whether the provided gRPC error was caused by the connection rather than the node
+// handling the call.
+func isTransportError(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+// isTxInMempoolCache reports whether the node rejected a tx because it has already received it. A broadcast
+// retried after a transport failure returns this if the previous attempt reached the node.
+func isTxInMempoolCache(res *sdk.TxResponse) bool {
+	return res.Codespace == sdkerrors.ErrTxInMempoolCache.Codespace() && res.Code == sdkerrors.ErrTxInMempoolCache.ABCICode()
+}
@@ -0,0 +1,141 @@
+package broadcaster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithRetry(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	tests := []struct {
+		name      string
+		retries   int
+		errs      []error
+		wantCalls int
+		wantCode  codes.Code
+	}{
+		{
+			name:      "success",
+			retries:   2,
+			errs:      []error{nil},
+			wantCalls: 1,
+			wantCode:  codes.OK,
+		},
+		{
+			name:      "success after transport failure",
+			retries:   2,
+			errs:      []error{unavailable, nil},
+			wantCalls: 2,
+			wantCode:  codes.OK,
+		},
+		{
+			name:      "retries exhausted",
+			retries:   2,
+			errs:      []error{unavailable, unavailable, unavailable, nil},
+			wantCalls: 3,
+			wantCode:  codes.Unavailable,
+		},
+		{
+			name:      "retries disabled",
+			errs:      []error{unavailable, nil},
+			wantCalls: 1,
+			wantCode:  codes.Unavailable,
+		},
+		{
+			name:      "not found is not retried",
+			retries:   2,
+			errs:      []error{status.Error(codes.NotFound, "account not found"), nil},
+			wantCalls: 1,
+			wantCode:  codes.NotFound,
+		},
+		{
+			name:      "deadline exceeded is not retried",
+			retries:   2,
+			errs:      []error{status.Error(codes.DeadlineExceeded, "deadline exceeded"), nil},
+			wantCalls: 1,
+			wantCode:  codes.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			opts := RetryOptions{Retries: tt.retries, Delay: time.Millisecond}
+
+			_, err := withRetry(context.Background(), nil, opts, "Test", func() (struct{}, error) {
+				err := tt.errs[calls]
+				calls++
+				return struct{}{}, err
+			})
+
+			if calls != tt.wantCalls {
+				t.Fatalf("got %d calls, want %d", calls, tt.wantCalls)
+			}
+
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("got code %s, want %s", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestWithRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls int
+	_, err := withRetry(ctx, nil, RetryOptions{Retries: 5, Delay: time.Hour}, "Test", func() (struct{}, error) {
+		calls++
+		return struct{}{}, status.Error(codes.Unavailable, "connection refused")
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+
+	if calls != 1 {
+		t.Fatalf("got %d calls, want 1", calls)
+	}
+}
+
+func TestIsTxInMempoolCache(t *testing.T) {
+	tests := []struct {
+		name string
+		res  *sdk.TxResponse
+		want bool
+	}{
+		{
+			name: "tx in mempool cache",
+			res:  &sdk.TxResponse{Codespace: sdkerrors.ErrTxInMempoolCache.Codespace(), Code: sdkerrors.ErrTxInMempoolCache.ABCICode()},
+			want: true,
+		},
+		{
+			name: "accepted",
+			res:  &sdk.TxResponse{},
+		},
+		{
+			name: "mempool full",
+			res:  &sdk.TxResponse{Codespace: sdkerrors.ErrMempoolIsFull.Codespace(), Code: sdkerrors.ErrMempoolIsFull.ABCICode()},
+		},
+		{
+			name: "same code in another codespace",
+			res:  &sdk.TxResponse{Codespace: "hyperlane", Code: sdkerrors.ErrTxInMempoolCache.ABCICode()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTxInMempoolCache(tt.res); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,9 @@ set -euo pipefail
 # HYP_KEY is the priv key of the EVM account used for Hyperlane contract deployment
 export HYP_KEY=0x82bfcfadbf1712f6550d8d2c00a39f05b33ec78939d0167be2a737d691f33a6a
 
+# HYP_MNEMONIC is the mnemonic of the celestia account used for cosmosnative deployment (the hyp key in genesis)
+export HYP_MNEMONIC="sphere exhibit essay fancy okay tuna leaf culture elbow drum trip exchange scorpion excuse parent sun make spot chunk mouse tenant shoe hurt scale"
+
 CONFIG_FILE="hyperlane-cosmosnative.json"
 
 if [[ ! -f "$CONFIG_FILE" ]]; then
@@ -25,7 +28,7 @@ if [[ ! -f "$CONFIG_FILE" ]]; then
   hyperlane warp deploy --config ./configs/warp-config.yaml --registry ./registry --yes
 
   echo "Deploying Hyperlane NoopISM stack on cosmosnative..."
-  hyp deploy-noopism celestia-validator:9090
+  hyp deploy-noopism celestia-validator:9090 --grpc-insecure
 
   echo "Configuring remote router for warp route on EVM..."
   cast send 0x345a583028762De4d733852c9D4f419077093A48 \
@@ -41,7 +44,7 @@ if [[ ! -f "$CONFIG_FILE" ]]; then
   echo "Successfully registered remote router address for domain 69420: $router_addr"
 
   echo "Configuring remote router for warp route on cosmosnative..."
-  hyp enroll-remote-router celestia-validator:9090 0x726f757465725f61707000000000000000000000000000010000000000000000 1234 0x000000000000000000000000345a583028762De4d733852c9D4f419077093A48
+  hyp enroll-remote-router celestia-validator:9090 0x726f757465725f61707000000000000000000000000000010000000000000000 1234 0x000000000000000000000000345a583028762De4d733852c9D4f419077093A48 --grpc-insecure
 
 else
   echo "Skipping deployment: $CONFIG_FILE already exists."